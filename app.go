@@ -1,12 +1,13 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
-	"os/signal"
 	"path"
+	"sync/atomic"
 	"time"
 )
 
@@ -37,14 +38,55 @@ type App struct {
 	Version string
 	// Boolean to hide built-in version flag and the VERSION section of help
 	HideVersion bool
-	// Signals are the signals that we want to handle
+	// Signals are the signals that are passed to OnSignal
 	Signals []os.Signal
+	// ShutdownSignals are the signals that shut the application down. The
+	// first one cancels the context and calls OnSignal and a repeated one
+	// forces the exit after the ShutdownTimeout
+	ShutdownSignals []os.Signal
+	// SignalHandlers handle the signals that do not shut the application
+	// down, e.g. SIGUSR1 for a dump
+	SignalHandlers map[os.Signal]SignalFunc
+	// SignalChannel replaces the notifications of the operating system. It is
+	// used to send the signals in the tests
+	SignalChannel <-chan os.Signal
+	// ShutdownTimeout is the time the command has to return after a repeated
+	// shutdown signal before the exit is forced. Defaults to
+	// DefaultShutdownTimeout
+	ShutdownTimeout time.Duration
 	// List of commands to execute
 	Commands []*Command
+	// AllowPrefixMatching resolves commands at every level by an unambiguous
+	// prefix of their name
+	AllowPrefixMatching bool
+	// Interactive prompts for the missing required flags of every command
+	// when the input is a terminal
+	Interactive bool
+	// Interpolate expands the references to the environment variables and to
+	// the other flags in the values and the paths of the flags of every
+	// command
+	Interpolate bool
+	// HelpWidth is the width the help is wrapped to. It defaults to the
+	// terminal width and a negative value turns the wrapping off
+	HelpWidth int
+	// HelpTemplate replaces the embedded template of the application help
+	HelpTemplate string
+	// VersionTemplate replaces the embedded template of the version command
+	VersionTemplate string
+	// TemplateFuncs are merged into the functions of the help and version
+	// templates
+	TemplateFuncs map[string]interface{}
+	// Locale selects the message catalog of the built-in texts. It defaults
+	// to the locale of the environment
+	Locale string
 	// List of flags to parse
 	Flags []Flag
 	// Providers contains a list of all providers
 	Providers []Provider
+	// Precedence replaces the built-in providers of all commands. They are
+	// applied in order, so a later provider overrides the values set by an
+	// earlier one. A non nil empty list turns the built-in providers off
+	Precedence []Provider
 	// An action to execute before any subcommands are run, but after the context is ready
 	// If a non-nil error is returned, no subcommands are run
 	Before BeforeFunc
@@ -58,145 +100,256 @@ type App struct {
 	// The action to execute when no subcommands are specified
 	// Expects a `cli.ActionFunc` but will accept the *deprecated* signature of `func(*cli.Context) {}`
 	Action ActionFunc
+	// Middleware wraps the actions of the application and all commands
+	Middleware []MiddlewareFunc
 	// Strategy enables comman retry logic
 	Strategy BackOffStrategy
-	// OnSignal occurs on system signal
+	// OnSignal occurs on every one of the Signals and on the first shutdown
+	// signal
 	OnSignal SignalFunc
 	// Execute this function if a usage error occurs.
 	OnUsageError UsageErrorFunc
 	// OnCommandNotFound is executed if the proper command cannot be found
 	OnCommandNotFound CommandNotFoundFunc
+	// Plugins adds the executables named after the application and a
+	// command, e.g. app-deploy, as commands
+	Plugins bool
+	// PluginDirs are searched for the plugins before the PATH
+	PluginDirs []string
+	// AliasPath is the path of the YAML file with the user-defined aliases
+	// of the commands, e.g. ${HOME}/.config/app/config.yaml
+	AliasPath string
+	// OnReload is executed when the values of the flags read from files are
+	// reloaded while the action runs. The files are checked at the
+	// ReloadInterval and when one of the ReloadSignals is received
+	OnReload ReloadFunc
+	// ReloadInterval is the interval the files are polled at. Zero turns the
+	// polling off
+	ReloadInterval time.Duration
+	// ReloadSignals are the signals that reload the files, e.g. SIGHUP. They
+	// are received from the SignalChannel when it is set
+	ReloadSignals []os.Signal
 	// Execute this function to handle ExitErrors. If not provided, HandleExitCoder is provided to
 	// function as a default, so this is optional.
 	OnExitError ExitErrorHandlerFunc
 	// Exit is the function used when the app exits. If not set defaults to os.Exit.
 	Exit ExitFunc
+	// Reader reads the input. Defaults to os.Stdin.
+	Reader io.Reader
 	// Writer writer to write output to
 	Writer io.Writer
 	// ErrWriter writes error output
 	ErrWriter io.Writer
+	// Terminal prompts for missing flag values. Defaults to a terminal for
+	// the Reader if it is a file.
+	Terminal Terminal
+	// LookupEnv retrieves the environment variables. Defaults to os.LookupEnv.
+	LookupEnv func(key string) (string, bool)
+	// Environ lists the environment variables in the form key=value. It is
+	// used to pass the environment to the plugins. Defaults to os.Environ.
+	Environ func() []string
+}
+
+// Use adds middleware that wraps the actions of the application and all
+// commands
+func (app *App) Use(middleware ...MiddlewareFunc) {
+	app.Middleware = append(app.Middleware, middleware...)
 }
 
 // Run is the entry point to the cli app. Parses the arguments slice and routes
 // to the proper flag/args combination
 func (app *App) Run(args []string) {
-	args = app.prepare(args)
+	run := *app
 
-	cmd := &Command{
-		Name:              app.Name,
-		Usage:             app.Usage,
-		UsageText:         app.UsageText,
-		HideHelp:          app.HideHelp,
-		HelpName:          app.HelpName,
-		Commands:          app.Commands,
-		Description:       app.Description,
-		ArgsUsage:         app.ArgsUsage,
-		Flags:             app.Flags,
-		Before:            app.Before,
-		After:             app.After,
-		BeforeInit:        app.BeforeInit,
-		AfterInit:         app.AfterInit,
-		Action:            app.Action,
-		Strategy:          app.Strategy,
-		Providers:         app.Providers,
-		OnUsageError:      app.OnUsageError,
-		OnCommandNotFound: app.OnCommandNotFound,
-		Metadata: Map{
-			"HideVersion": app.HideVersion,
-			"Version":     app.Version,
-			"Authors":     app.Authors,
-			"Copyright":   app.Copyright,
-		},
+	if run.ErrWriter == nil {
+		run.ErrWriter = os.Stderr
 	}
 
-	ctx := &Context{
-		Command:   cmd,
-		Args:      args[1:],
-		Writer:    app.Writer,
-		ErrWriter: app.ErrWriter,
-		Metadata:  make(map[string]interface{}),
+	if run.Exit == nil {
+		run.Exit = os.Exit
 	}
 
-	app.notify(ctx)
-	app.error(cmd.RunWithContext(ctx))
+	run.error(run.RunContext(context.Background(), args))
 }
 
-func (app *App) notify(ctx *Context) {
-	if len(app.Signals) == 0 {
-		return
-	}
+// RunContext runs the app like Run, but it returns the error instead of
+// writing it to ErrWriter and calling Exit. The returned error is an
+// ExitCoder. The running command is cancelled along with the context. Nothing
+// is written to ErrWriter unless it is set. The commands and the flags of the
+// app are not changed by the run, so it can run more than once.
+func (app *App) RunContext(parent context.Context, args []string) error {
+	cmd, ctx := app.prepare(args)
 
-	if app.OnSignal == nil {
-		return
+	ctx.base, ctx.cancel = context.WithCancel(parent)
+	defer ctx.cancel()
+
+	ctx.current = &atomic.Pointer[Context]{}
+
+	// the custom templates are checked before anything runs
+	if err := cmd.templates(nil); err != nil {
+		return app.exitError(err)
 	}
 
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, app.Signals...)
+	stop, forced := app.notify(ctx)
+	err := app.run(cmd, ctx, forced)
 
-	go func() {
-		signal := <-ch
+	// the error of the signal handler is reported if the command succeeds
+	if errx := stop(); err == nil {
+		err = errx
+	}
 
-		err := app.OnSignal(ctx, signal)
-		app.error(err)
-	}()
+	return app.exitError(err)
 }
 
-func (app *App) prepare(args []string) []string {
-	app.flags()
-	app.commands()
-	return app.app(args)
+// exitError converts the error to ExitCoder
+func (app *App) exitError(err error) error {
+	if err != nil && app.OnExitError != nil {
+		err = app.OnExitError(err)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(ExitCoder); ok {
+		return err
+	}
+
+	var errx ExitCoder
+
+	if errors.As(err, &errx) {
+		return WrapError(err).WithCode(errx.Code())
+	}
+
+	return WrapError(err)
 }
 
-func (app *App) app(args []string) []string {
+// prepare creates the root command and its context
+func (app *App) prepare(args []string) (*Command, *Context) {
 	if len(args) == 0 {
 		args = []string{"unknown"}
 	}
 
-	if app.Name == "" {
-		app.Name = path.Base(args[0])
-	}
+	compiled := app.Compiled
 
-	if app.Compiled.IsZero() {
-		info, err := os.Stat(args[0])
+	if compiled.IsZero() {
+		compiled = time.Now()
 
-		if err != nil {
-			app.Compiled = time.Now()
-		} else {
-			app.Compiled = info.ModTime()
+		if info, err := os.Stat(args[0]); err == nil {
+			compiled = info.ModTime()
 		}
 	}
 
-	if app.Writer == nil {
-		app.Writer = os.Stdout
+	cmd := &Command{
+		Name:                app.Name,
+		Usage:               app.Usage,
+		UsageText:           app.UsageText,
+		HideHelp:            app.HideHelp,
+		HelpName:            app.HelpName,
+		Commands:            app.commands(),
+		Description:         app.Description,
+		ArgsUsage:           app.ArgsUsage,
+		Flags:               app.flags(),
+		Before:              app.Before,
+		After:               app.After,
+		BeforeInit:          app.BeforeInit,
+		AfterInit:           app.AfterInit,
+		Action:              app.Action,
+		Middleware:          app.Middleware,
+		Strategy:            app.Strategy,
+		Providers:           app.Providers,
+		Precedence:          app.Precedence,
+		OnUsageError:        app.OnUsageError,
+		OnCommandNotFound:   app.OnCommandNotFound,
+		Plugins:             app.Plugins,
+		PluginDirs:          app.PluginDirs,
+		AliasPath:           app.AliasPath,
+		OnReload:            app.OnReload,
+		ReloadInterval:      app.ReloadInterval,
+		ReloadSignals:       app.ReloadSignals,
+		AllowPrefixMatching: app.AllowPrefixMatching,
+		Interactive:         app.Interactive,
+		Interpolate:         app.Interpolate,
+		HelpWidth:           app.HelpWidth,
+		HelpTemplate:        app.HelpTemplate,
+		TemplateFuncs:       app.TemplateFuncs,
+		Metadata: Map{
+			"HideVersion":     app.HideVersion,
+			"Version":         app.Version,
+			"VersionTemplate": app.VersionTemplate,
+			"Authors":         app.Authors,
+			"Copyright":       app.Copyright,
+			"Compiled":        compiled,
+		},
+	}
+
+	if cmd.Name == "" {
+		cmd.Name = path.Base(args[0])
+	}
+
+	ctx := &Context{
+		Command:   cmd,
+		Args:      args[1:],
+		Reader:    app.Reader,
+		Writer:    app.Writer,
+		ErrWriter: app.ErrWriter,
+		Terminal:  app.Terminal,
+		LookupEnv: app.LookupEnv,
+		Environ:   app.Environ,
+		Locale:    app.Locale,
+		Metadata:  make(map[string]interface{}),
+	}
+
+	if ctx.Reader == nil {
+		ctx.Reader = os.Stdin
 	}
 
-	if app.ErrWriter == nil {
-		app.ErrWriter = os.Stderr
+	if ctx.Writer == nil {
+		ctx.Writer = os.Stdout
 	}
 
-	if app.Exit == nil {
-		app.Exit = os.Exit
+	if ctx.ErrWriter == nil {
+		ctx.ErrWriter = io.Discard
 	}
 
-	return args
+	if ctx.LookupEnv == nil {
+		ctx.LookupEnv = os.LookupEnv
+	}
+
+	if ctx.Environ == nil {
+		ctx.Environ = os.Environ
+	}
+
+	if file, ok := ctx.Reader.(*os.File); ok && ctx.Terminal == nil {
+		ctx.Terminal = NewTerminal(file, ctx.ErrWriter)
+	}
+
+	return cmd, ctx
 }
 
-func (app *App) flags() {
+func (app *App) flags() []Flag {
+	flags := append([]Flag{}, app.Flags...)
+
 	if !app.HideVersion {
 		version := &BoolFlag{
 			Name:  "version, v",
-			Usage: "prints the version",
+			Usage: "flag.version",
 		}
 
-		app.Flags = append(app.Flags, version)
+		flags = append(flags, version)
 	}
+
+	return flags
 }
 
-func (app *App) commands() {
+func (app *App) commands() []*Command {
+	commands := append([]*Command{}, app.Commands...)
+
 	if !app.HideVersion {
-		version := NewVersionCommand()
-		app.Commands = append(app.Commands, version)
+		commands = append(commands, NewVersionCommand())
 	}
+
+	return commands
 }
 
 func (app *App) error(err error) {
@@ -204,19 +357,17 @@ func (app *App) error(err error) {
 		return
 	}
 
-	if app.OnExitError != nil {
-		err = app.OnExitError(err)
-	}
+	locale := app.Locale
 
-	fmt.Fprintln(app.ErrWriter, err)
+	if locale == "" {
+		locale = environLocale(app.LookupEnv)
+	}
 
-	var errx *ExitError
+	fmt.Fprintln(app.ErrWriter, localize(locale, err))
 
-	if !errors.As(err, &errx) {
-		errx = WrapError(err)
+	if errx, ok := err.(ExitCoder); ok {
+		app.Exit(errx.Code())
 	}
-
-	app.Exit(errx.Code())
 }
 
 // Author represents someone who has contributed to a cli project.
@@ -19,9 +19,16 @@ type AfterFunc func(*Context) error
 // ActionFunc is the action to execute when no subcommands are specified
 type ActionFunc func(*Context) error
 
+// MiddlewareFunc wraps an action, e.g. to measure, authorize or log it
+type MiddlewareFunc func(ActionFunc) ActionFunc
+
 // SignalFunc is an action to execute after a system signal
 type SignalFunc func(*Context, os.Signal) error
 
+// ReloadFunc is executed after the values of the flags have been reloaded
+// from their files. It receives the names of the changed flags.
+type ReloadFunc func(*Context, []string)
+
 // OnUsageErrorFunc is executed if an usage error occurs. This is useful for displaying
 // customized usage error messages.  This function is able to replace the
 // original error messages.  If this function is not set, the "Incorrect usage"
@@ -41,17 +48,25 @@ type ExitFunc func(code int)
 // OneOf returns a validator that expectes the flag value to matches one of the
 // provided values
 func OneOf(items ...interface{}) Validator {
-	fn := func(ctx *Context, value interface{}) error {
-		for _, item := range items {
-			if item == value {
-				return nil
-			}
-		}
+	return &OneOfValidator{Items: items}
+}
 
-		return fmt.Errorf("unsupported value: %v", value)
+var _ Validator = &OneOfValidator{}
+
+// OneOfValidator expects the flag value to match one of the items
+type OneOfValidator struct {
+	Items []interface{}
+}
+
+// Validate validates the value
+func (v *OneOfValidator) Validate(ctx *Context, value interface{}) error {
+	for _, item := range v.Items {
+		if item == value {
+			return nil
+		}
 	}
 
-	return ValidatorFunc(fn)
+	return newError(ExitCodeErrorApp, "error.value", value)
 }
 
 // EnvOf formats a list of environment variables
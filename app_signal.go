@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// DefaultShutdownTimeout is the time the command has to return after a
+// repeated shutdown signal when the ShutdownTimeout of the App is zero
+const DefaultShutdownTimeout = 10 * time.Second
+
+// notify handles the signals until the returned function is called. The
+// function returns the error of the OnSignal handler. The channel receives
+// an error when the exit is forced.
+func (app *App) notify(ctx *Context) (func() error, <-chan error) {
+	signals := append(append([]os.Signal{}, app.Signals...), app.ShutdownSignals...)
+
+	for sig := range app.SignalHandlers {
+		signals = append(signals, sig)
+	}
+
+	if len(signals) == 0 && app.SignalChannel == nil {
+		return func() error { return nil }, nil
+	}
+
+	var (
+		ch      = app.SignalChannel
+		stop    = make(chan struct{})
+		done    = make(chan error, 1)
+		forced  = make(chan error, 1)
+		release = func() {}
+		router  = &router{}
+	)
+
+	if ch == nil {
+		notify := make(chan os.Signal, 1)
+		signal.Notify(notify, signals...)
+
+		ch = notify
+		router.notify = notify
+		release = func() { signal.Stop(notify) }
+	}
+
+	// the reload signals of the commands are received by the application
+	ctx.router = router
+
+	go func() {
+		var (
+			err      error
+			shutdown bool
+			grace    <-chan time.Time
+		)
+
+		for {
+			select {
+			case sig, ok := <-ch:
+				// a closed channel does not send any more signals
+				if !ok {
+					ch = nil
+					continue
+				}
+
+				if handler, ok := app.SignalHandlers[sig]; ok {
+					if err := handler(ctx, sig); err != nil {
+						ctx.warn("warning.signal", sig, err)
+					}
+
+					continue
+				}
+
+				if router.route(sig) {
+					continue
+				}
+
+				switch {
+				case !listed(app.ShutdownSignals, sig):
+					// the other signals are only passed to OnSignal
+					if !listed(app.Signals, sig) {
+						continue
+					}
+				case shutdown:
+					// the command has some time to complete the After hooks
+					if grace == nil {
+						grace = time.After(app.shutdownTimeout())
+					}
+
+					continue
+				default:
+					shutdown = true
+					ctx.signal(sig)
+				}
+
+				if app.OnSignal == nil {
+					continue
+				}
+
+				if errx := app.OnSignal(ctx, sig); errx != nil {
+					err = errx
+				}
+			case <-grace:
+				forced <- SignalError(ctx.Signal)
+				grace = nil
+			case <-stop:
+				done <- err
+				return
+			}
+		}
+	}()
+
+	return func() error {
+		release()
+		close(stop)
+		return <-done
+	}, forced
+}
+
+// router delivers the signals received by the application to the commands
+// that subscribe to them
+type router struct {
+	mu     sync.Mutex
+	routes map[chan<- os.Signal][]os.Signal
+	// notify receives the signals of the operating system. It is nil when
+	// the signals are sent to the SignalChannel of the application.
+	notify chan<- os.Signal
+}
+
+// subscribe sends the signals to the channel until the returned function is
+// called
+func (r *router) subscribe(ch chan<- os.Signal, signals []os.Signal) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.routes == nil {
+		r.routes = make(map[chan<- os.Signal][]os.Signal)
+	}
+
+	if r.notify != nil {
+		signal.Notify(r.notify, signals...)
+	}
+
+	r.routes[ch] = signals
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		delete(r.routes, ch)
+	}
+}
+
+// route sends the signal to the subscribed channels without blocking. It
+// returns true if any of them is subscribed to the signal.
+func (r *router) route(sig os.Signal) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	routed := false
+
+	for ch, signals := range r.routes {
+		for _, item := range signals {
+			if item != sig {
+				continue
+			}
+
+			select {
+			case ch <- sig:
+			default:
+			}
+
+			routed = true
+		}
+	}
+
+	return routed
+}
+
+// notify sends the signals to the channel until the returned function is
+// called. The signals are received by the application if it handles them.
+func (ctx *Context) notify(ch chan<- os.Signal, signals []os.Signal) func() {
+	if ctx.router != nil {
+		return ctx.router.subscribe(ch, signals)
+	}
+
+	signal.Notify(ch, signals...)
+
+	return func() {
+		signal.Stop(ch)
+	}
+}
+
+// listed returns true if the signal is one of the signals
+func listed(signals []os.Signal, sig os.Signal) bool {
+	for _, item := range signals {
+		if item == sig {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (app *App) shutdownTimeout() time.Duration {
+	if app.ShutdownTimeout == 0 {
+		return DefaultShutdownTimeout
+	}
+
+	return app.ShutdownTimeout
+}
+
+// run runs the command until it returns or the exit is forced
+func (app *App) run(cmd *Command, ctx *Context, forced <-chan error) error {
+	if forced == nil {
+		return cmd.RunWithContext(ctx)
+	}
+
+	result := make(chan error, 1)
+
+	go func() {
+		result <- cmd.RunWithContext(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case err := <-forced:
+		return err
+	}
+}
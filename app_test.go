@@ -2,10 +2,13 @@ package cli_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/phogolabs/cli"
 
@@ -82,6 +85,18 @@ var _ = Describe("App", func() {
 
 			app.Signals = []os.Signal{syscall.SIGUSR1}
 			app.Action = func(ctx *cli.Context) error {
+				process, err := os.FindProcess(os.Getpid())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(process.Signal(syscall.SIGUSR1)).To(Succeed())
+
+				Eventually(func() int {
+					rw.RLock()
+					defer rw.RUnlock()
+					return count
+				}).Should(Equal(1))
+
+				// the signal does not cancel the running command
+				Consistently(ctx.Done()).ShouldNot(BeClosed())
 				return nil
 			}
 
@@ -101,25 +116,212 @@ var _ = Describe("App", func() {
 
 			app.Run([]string{"app"})
 
-			process, err := os.FindProcess(os.Getpid())
-			Expect(err).NotTo(HaveOccurred())
-			Expect(process.Signal(syscall.SIGUSR1)).To(Succeed())
-
 			Eventually(func() int {
 				rw.RLock()
 				defer rw.RUnlock()
 				return count
 			}).Should(Equal(1))
 		})
+
+		Context("when the signals are sent to the channel", func() {
+			var signals chan os.Signal
+
+			BeforeEach(func() {
+				signals = make(chan os.Signal, 3)
+
+				app.ShutdownSignals = []os.Signal{os.Interrupt}
+				app.SignalChannel = signals
+			})
+
+			It("sets the signal and runs the After hooks", func() {
+				var handled []os.Signal
+
+				app.OnSignal = func(ctx *cli.Context, signal os.Signal) error {
+					handled = append(handled, signal)
+					return nil
+				}
+
+				app.Commands[0].Action = func(ctx *cli.Context) error {
+					signals <- os.Interrupt
+
+					Eventually(ctx.Done()).Should(BeClosed())
+					Expect(ctx.Signal).To(Equal(os.Interrupt))
+					Expect(ctx.Parent.Signal).To(Equal(os.Interrupt))
+					return nil
+				}
+
+				after := false
+
+				app.Commands[0].After = func(ctx *cli.Context) error {
+					after = true
+					return nil
+				}
+
+				Expect(app.RunContext(context.Background(), []string{"app", "sync"})).To(Succeed())
+				Expect(handled).To(Equal([]os.Signal{os.Interrupt}))
+				Expect(after).To(BeTrue())
+			})
+
+			It("returns the error of the signal handler", func() {
+				app.OnSignal = func(ctx *cli.Context, signal os.Signal) error {
+					return cli.NewExitError("interrupted", 130)
+				}
+
+				app.Action = func(ctx *cli.Context) error {
+					signals <- os.Interrupt
+
+					Eventually(ctx.Done()).Should(BeClosed())
+					return nil
+				}
+
+				err := app.RunContext(context.Background(), []string{"app"})
+				Expect(err).To(MatchError("interrupted"))
+				Expect(err.(cli.ExitCoder).Code()).To(Equal(130))
+			})
+
+			It("handles every signal with its handler", func() {
+				var handled []os.Signal
+
+				app.SignalHandlers = map[os.Signal]cli.SignalFunc{
+					syscall.SIGHUP: func(ctx *cli.Context, signal os.Signal) error {
+						handled = append(handled, signal)
+						return nil
+					},
+				}
+
+				app.Action = func(ctx *cli.Context) error {
+					signals <- syscall.SIGHUP
+					signals <- syscall.SIGHUP
+
+					Eventually(func() int { return len(signals) }).Should(BeZero())
+					Consistently(ctx.Done()).ShouldNot(BeClosed())
+					return nil
+				}
+
+				Expect(app.RunContext(context.Background(), []string{"app"})).To(Succeed())
+				Expect(handled).To(Equal([]os.Signal{syscall.SIGHUP, syscall.SIGHUP}))
+			})
+
+			It("reloads the flags on the reload signal", func() {
+				path := filepath.Join(GinkgoT().TempDir(), "level")
+				Expect(os.WriteFile(path, []byte("info"), 0o600)).To(Succeed())
+
+				reloaded := make(chan []string, 1)
+
+				app.Flags = []cli.Flag{
+					&cli.StringFlag{Name: "level", Path: path},
+				}
+
+				app.ReloadSignals = []os.Signal{syscall.SIGHUP}
+				app.OnReload = func(ctx *cli.Context, names []string) {
+					reloaded <- names
+				}
+
+				app.Action = func(ctx *cli.Context) error {
+					Expect(os.WriteFile(path, []byte("debug"), 0o600)).To(Succeed())
+					signals <- syscall.SIGHUP
+
+					Eventually(reloaded).Should(Receive(Equal([]string{"level"})))
+					Expect(ctx.String("level")).To(Equal("debug"))
+					return nil
+				}
+
+				Expect(app.RunContext(context.Background(), []string{"app"})).To(Succeed())
+			})
+
+			Context("when the channel is closed", func() {
+				It("runs the command", func() {
+					app.Action = func(ctx *cli.Context) error {
+						close(signals)
+
+						Consistently(ctx.Done()).ShouldNot(BeClosed())
+						return nil
+					}
+
+					Expect(app.RunContext(context.Background(), []string{"app"})).To(Succeed())
+				})
+			})
+
+			Context("when the signal is repeated", func() {
+				It("forces the exit after the timeout", func() {
+					release := make(chan struct{})
+					defer close(release)
+
+					app.ShutdownTimeout = 10 * time.Millisecond
+					app.Action = func(ctx *cli.Context) error {
+						signals <- os.Interrupt
+						signals <- os.Interrupt
+
+						// the command does not complete in time
+						<-release
+						return nil
+					}
+
+					err := app.RunContext(context.Background(), []string{"app"})
+					Expect(err).To(MatchError("forced exit on signal 'interrupt'"))
+					Expect(err.(cli.ExitCoder).Code()).To(Equal(cli.ExitCodeErrorSignal))
+				})
+
+				It("waits for the default timeout", func() {
+					app.Action = func(ctx *cli.Context) error {
+						signals <- os.Interrupt
+						signals <- os.Interrupt
+
+						Eventually(func() int { return len(signals) }).Should(BeZero())
+						Consistently(func() int { return len(signals) }, "100ms").Should(BeZero())
+						return nil
+					}
+
+					Expect(app.RunContext(context.Background(), []string{"app"})).To(Succeed())
+				})
+
+				It("does not force the exit if the command completes", func() {
+					app.ShutdownTimeout = time.Second
+					app.Action = func(ctx *cli.Context) error {
+						signals <- os.Interrupt
+						signals <- os.Interrupt
+
+						Eventually(func() int { return len(signals) }).Should(BeZero())
+						return nil
+					}
+
+					Expect(app.RunContext(context.Background(), []string{"app"})).To(Succeed())
+				})
+			})
+		})
+	})
+
+	Context("when the prefix matching is allowed", func() {
+		It("resolves the commands at every level", func() {
+			executed := false
+
+			app.AllowPrefixMatching = true
+			app.Commands = []*cli.Command{
+				&cli.Command{
+					Name: "deploy",
+					Commands: []*cli.Command{
+						&cli.Command{
+							Name: "list",
+							Action: func(ctx *cli.Context) error {
+								executed = true
+								return nil
+							},
+						},
+					},
+				},
+			}
+
+			app.Run([]string{"app", "dep", "li"})
+			Expect(executed).To(BeTrue())
+		})
 	})
 
 	Context("when the app name is not provided", func() {
-		It("sets the app name", func() {
+		It("uses the program name", func() {
 			app.Name = ""
 
 			app.Action = func(ctx *cli.Context) error {
 				cmd := ctx.Command
-				Expect(cmd.Name).To(Equal(app.Name))
 				Expect(cmd.Name).To(Equal("app"))
 				return nil
 			}
@@ -143,6 +345,29 @@ var _ = Describe("App", func() {
 		})
 	})
 
+	Context("when the custom template is not valid", func() {
+		It("exits with the exit code 1006", func() {
+			executed := false
+
+			app.Action = func(ctx *cli.Context) error {
+				executed = true
+				return nil
+			}
+
+			app.Commands[0].HelpTemplate = "{{.Name"
+			app.ErrWriter = &bytes.Buffer{}
+
+			app.Exit = func(code int) {
+				Expect(code).To(Equal(cli.ExitCodeErrorTemplate))
+			}
+
+			app.Run([]string{"app"})
+
+			Expect(executed).To(BeFalse())
+			Expect(app.ErrWriter).To(ContainSubstring("invalid template of 'sync'"))
+		})
+	})
+
 	Context("when the app fails", func() {
 		It("exits with the provided code", func() {
 			app.Action = func(ctx *cli.Context) error {
@@ -175,6 +400,132 @@ var _ = Describe("App", func() {
 			})
 		})
 	})
+
+	Describe("RunContext", func() {
+		It("returns the exit error", func() {
+			app.Action = func(ctx *cli.Context) error {
+				return fmt.Errorf("oh no")
+			}
+
+			app.Exit = func(code int) {
+				Fail("the app should not exit")
+			}
+
+			err := app.RunContext(context.Background(), []string{"app"})
+			Expect(err).To(MatchError("oh no"))
+
+			errx, ok := err.(cli.ExitCoder)
+			Expect(ok).To(BeTrue())
+			Expect(errx.Code()).To(Equal(cli.ExitCodeErrorApp))
+		})
+
+		It("writes the error to ErrWriter only if it is set", func() {
+			app.Action = func(ctx *cli.Context) error {
+				return fmt.Errorf("oh no")
+			}
+
+			Expect(app.RunContext(context.Background(), []string{"app"})).To(HaveOccurred())
+			Expect(app.ErrWriter).To(BeNil())
+		})
+
+		It("can be called more than once", func() {
+			count := 0
+
+			app.Action = func(ctx *cli.Context) error {
+				count++
+				return nil
+			}
+
+			Expect(app.RunContext(context.Background(), []string{"app"})).To(Succeed())
+			Expect(app.RunContext(context.Background(), []string{"app", "--version"})).To(Succeed())
+			Expect(app.RunContext(context.Background(), []string{"app"})).To(Succeed())
+
+			Expect(count).To(Equal(2))
+			Expect(app.Flags).To(BeEmpty())
+			Expect(app.Commands).To(HaveLen(1))
+			Expect(app.Providers).To(BeEmpty())
+			Expect(app.Compiled.IsZero()).To(BeTrue())
+		})
+
+		It("does not keep the values of the flags of the previous run", func() {
+			names := []string{}
+
+			app.Flags = []cli.Flag{
+				&cli.StringFlag{
+					Name:  "name",
+					Value: "default",
+				},
+			}
+
+			app.Action = func(ctx *cli.Context) error {
+				names = append(names, ctx.String("name"))
+				return nil
+			}
+
+			Expect(app.RunContext(context.Background(), []string{"app", "--name", "x"})).To(Succeed())
+			Expect(app.RunContext(context.Background(), []string{"app"})).To(Succeed())
+
+			Expect(names).To(Equal([]string{"x", "default"}))
+			Expect(app.Flags[0].(*cli.StringFlag).Value).To(Equal("default"))
+		})
+
+		It("does not keep the documents of the previous run", func() {
+			type Document struct {
+				A string
+				B string
+			}
+
+			var (
+				dir       = GinkgoT().TempDir()
+				document  = &Document{}
+				documents = []Document{}
+				tags      = [][]string{}
+			)
+
+			Expect(os.WriteFile(filepath.Join(dir, "one.json"), []byte(`{"A":"one"}`), 0o600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "two.json"), []byte(`{"B":"two"}`), 0o600)).To(Succeed())
+
+			app.Flags = []cli.Flag{
+				&cli.JSONFlag{
+					Name:  "document",
+					Value: document,
+				},
+				&cli.StringSliceFlag{
+					Name:  "tag",
+					Value: make([]string, 0, 4),
+				},
+			}
+
+			app.Action = func(ctx *cli.Context) error {
+				documents = append(documents, *ctx.Get("document").(*Document))
+				tags = append(tags, ctx.StringSlice("tag"))
+				return nil
+			}
+
+			Expect(app.RunContext(context.Background(), []string{"app", "--document", filepath.Join(dir, "one.json"), "--tag", "a"})).To(Succeed())
+			Expect(app.RunContext(context.Background(), []string{"app", "--document", filepath.Join(dir, "two.json")})).To(Succeed())
+
+			Expect(documents).To(Equal([]Document{{A: "one"}, {B: "two"}}))
+			Expect(document).To(Equal(&Document{}))
+			// the values are not appended to the array of the default
+			Expect(app.Flags[1].(*cli.StringSliceFlag).Value[:1]).To(Equal([]string{""}))
+			Expect(tags).To(Equal([][]string{{"a"}, {}}))
+		})
+
+		Context("when the context is cancelled", func() {
+			It("cancels the running command", func() {
+				parent, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				app.Action = func(ctx *cli.Context) error {
+					Expect(ctx.Done()).To(BeClosed())
+					return nil
+				}
+
+				Expect(app.RunContext(parent, []string{"app"})).To(Succeed())
+			})
+		})
+	})
 })
 
 var _ = Describe("Author", func() {
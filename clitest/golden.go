@@ -0,0 +1,49 @@
+package clitest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+)
+
+// UpdateEnv is the environment variable that rewrites the golden files with
+// the actual output when it is set to a non empty value
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// TestingT is the part of testing.TB used by the assertions. It is
+// implemented by *testing.T and by GinkgoT().
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// AssertGolden compares the output of the application with the content of
+// the golden file. The file is rewritten instead if UPDATE_GOLDEN is set.
+func (r *Result) AssertGolden(t TestingT, path string) {
+	t.Helper()
+
+	actual := []byte(r.Stdout)
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("cannot create the golden file directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, actual, 0o600); err != nil {
+			t.Fatalf("cannot update the golden file: %v", err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read the golden file: %v", err)
+		return
+	}
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("the output does not match the golden file %s\n--- expected\n%s\n--- actual\n%s", path, expected, actual)
+	}
+}
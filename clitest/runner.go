@@ -0,0 +1,174 @@
+// Package clitest runs cli applications in process and captures their
+// output and exit code.
+package clitest
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/phogolabs/cli"
+)
+
+// Runner runs an application with an isolated environment, input and files.
+// The environment of the process is never read nor changed.
+type Runner struct {
+	// App under test. Every run uses a copy of it with its own Reader,
+	// Writer, ErrWriter, Terminal, LookupEnv, Environ and Exit.
+	App *cli.App
+	// Env contains the environment variables visible to the application
+	Env map[string]string
+	// Stdin is the input of the application
+	Stdin string
+	// TTY makes the input look like an interactive terminal
+	TTY bool
+	// Files are written to Dir before every run. They are keyed by a path
+	// relative to Dir.
+	Files map[string]string
+	// Dir is the directory of the files
+	Dir string
+}
+
+// Result is the outcome of a run
+type Result struct {
+	// Stdout is the output of the application
+	Stdout string
+	// Stderr is the error output of the application
+	Stderr string
+	// ExitCode is the code the application exited with
+	ExitCode int
+}
+
+// NewRunner creates a runner for the application. The files are written to
+// the given directory, usually a temporary directory created by the test.
+func NewRunner(app *cli.App, dir string) *Runner {
+	return &Runner{
+		App:   app,
+		Env:   make(map[string]string),
+		Files: make(map[string]string),
+		Dir:   dir,
+	}
+}
+
+// Path returns the absolute path of a file in Dir
+func (r *Runner) Path(name string) string {
+	return filepath.Join(r.Dir, filepath.FromSlash(name))
+}
+
+// Run runs the application with the arguments. The name of the application
+// is prepended to them.
+func (r *Runner) Run(args ...string) (*Result, error) {
+	if err := r.write(); err != nil {
+		return nil, err
+	}
+
+	var (
+		stdin  = bufio.NewReader(strings.NewReader(r.Stdin))
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+		result = &Result{}
+		app    = *r.App
+	)
+
+	// the app is copied, so the streams of the run do not leak into it, and
+	// the prompts and the application share the input
+	app.Reader = stdin
+	app.Writer = stdout
+	app.ErrWriter = stderr
+	app.LookupEnv = r.lookup
+	app.Environ = r.environ
+	app.Terminal = &Terminal{
+		Reader: stdin,
+		Writer: stderr,
+		TTY:    r.TTY,
+	}
+	app.Exit = func(code int) {
+		result.ExitCode = code
+	}
+
+	name := app.Name
+
+	if name == "" {
+		name = "app"
+	}
+
+	app.Run(append([]string{name}, args...))
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	return result, nil
+}
+
+func (r *Runner) lookup(key string) (string, bool) {
+	value, ok := r.Env[key]
+	return value, ok
+}
+
+func (r *Runner) environ() []string {
+	variables := make([]string, 0, len(r.Env))
+
+	for key, value := range r.Env {
+		variables = append(variables, key+"="+value)
+	}
+
+	sort.Strings(variables)
+	return variables
+}
+
+func (r *Runner) write() error {
+	for name, content := range r.Files {
+		path := r.Path(name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var _ cli.Terminal = &Terminal{}
+
+// Terminal is a terminal that reads the answers to the prompts from a reader
+type Terminal struct {
+	// Reader is the input of the terminal
+	Reader *bufio.Reader
+	// Writer writes the prompts
+	Writer io.Writer
+	// TTY is returned by IsTerminal
+	TTY bool
+}
+
+// Write writes the prompt
+func (t *Terminal) Write(p []byte) (int, error) {
+	return t.Writer.Write(p)
+}
+
+// IsTerminal returns true if the terminal is interactive
+func (t *Terminal) IsTerminal() bool {
+	return t.TTY
+}
+
+// ReadLine reads a line of input
+func (t *Terminal) ReadLine() (string, error) {
+	line, err := t.Reader.ReadString('\n')
+
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// ReadPassword reads a line of input
+func (t *Terminal) ReadPassword() (string, error) {
+	return t.ReadLine()
+}
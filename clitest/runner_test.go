@@ -0,0 +1,230 @@
+package clitest_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/phogolabs/cli"
+	"github.com/phogolabs/cli/clitest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Runner", func() {
+	type Config struct {
+		Name string `json:"name"`
+	}
+
+	var (
+		app    *cli.App
+		runner *clitest.Runner
+	)
+
+	BeforeEach(func() {
+		app = &cli.App{
+			Name:        "greet",
+			Usage:       "greets the user",
+			HideVersion: true,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:   "greeting",
+					Value:  "hello",
+					EnvVar: "GREET_GREETING",
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				fmt.Fprintf(ctx.Writer, "%s world\n", ctx.String("greeting"))
+				return nil
+			},
+		}
+
+		runner = clitest.NewRunner(app, GinkgoT().TempDir())
+	})
+
+	It("captures the output and the exit code", func() {
+		result, err := runner.Run()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Stdout).To(Equal("hello world\n"))
+		Expect(result.Stderr).To(BeEmpty())
+		Expect(result.ExitCode).To(BeZero())
+	})
+
+	It("does not change the app", func() {
+		_, err := runner.Run()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(app.Writer).To(BeNil())
+		Expect(app.ErrWriter).To(BeNil())
+		Expect(app.LookupEnv).To(BeNil())
+		Expect(app.Exit).To(BeNil())
+	})
+
+	It("passes the arguments", func() {
+		result, err := runner.Run("--greeting", "hi")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Stdout).To(Equal("hi world\n"))
+	})
+
+	Context("when the environment is set", func() {
+		BeforeEach(func() {
+			runner.Env["GREET_GREETING"] = "howdy"
+		})
+
+		It("provides the environment to the application only", func() {
+			result, err := runner.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Stdout).To(Equal("howdy world\n"))
+			Expect(os.Getenv("GREET_GREETING")).To(BeEmpty())
+		})
+
+		It("provides the environment to the plugins", func() {
+			dir := GinkgoT().TempDir()
+			script := "#!/bin/sh\necho \"$GREET_USER\"\n"
+			Expect(os.WriteFile(filepath.Join(dir, "greet-env"), []byte(script), 0o700)).To(Succeed())
+
+			app.Plugins = true
+			app.PluginDirs = []string{dir}
+			runner.Env["GREET_USER"] = "john"
+
+			result, err := runner.Run("env")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Stdout).To(Equal("john\n"))
+		})
+	})
+
+	Context("when the application fails", func() {
+		BeforeEach(func() {
+			app.Action = func(ctx *cli.Context) error {
+				return cli.NewExitError("oh no", 42)
+			}
+		})
+
+		It("captures the error and the exit code", func() {
+			result, err := runner.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Stderr).To(Equal("oh no\n"))
+			Expect(result.ExitCode).To(Equal(42))
+		})
+	})
+
+	Context("when the application reads the input", func() {
+		BeforeEach(func() {
+			runner.Stdin = "john\n"
+
+			app.Action = func(ctx *cli.Context) error {
+				data, err := io.ReadAll(ctx.Reader)
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(ctx.Writer, "hello %s", data)
+				return nil
+			}
+		})
+
+		It("provides the input", func() {
+			result, err := runner.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Stdout).To(Equal("hello john\n"))
+		})
+	})
+
+	Context("when the application prompts", func() {
+		BeforeEach(func() {
+			runner.Stdin = "john\n"
+			runner.TTY = true
+
+			app.Interactive = true
+			app.Flags = []cli.Flag{
+				&cli.StringFlag{
+					Name:     "name",
+					Usage:    "Name",
+					Required: true,
+				},
+			}
+			app.Action = func(ctx *cli.Context) error {
+				fmt.Fprintf(ctx.Writer, "hello %s\n", ctx.String("name"))
+				return nil
+			}
+		})
+
+		It("answers the prompt with the input", func() {
+			result, err := runner.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Stderr).To(Equal("Name: "))
+			Expect(result.Stdout).To(Equal("hello john\n"))
+		})
+	})
+
+	Context("when the application reads files", func() {
+		BeforeEach(func() {
+			runner.Files["etc/config.json"] = `{"name": "john"}`
+
+			app.Flags = []cli.Flag{
+				&cli.JSONFlag{
+					Name:  "config",
+					Path:  runner.Path("etc/config.json"),
+					Value: &Config{},
+				},
+			}
+			app.Action = func(ctx *cli.Context) error {
+				config := ctx.Get("config").(*Config)
+				fmt.Fprintf(ctx.Writer, "hello %s\n", config.Name)
+				return nil
+			}
+		})
+
+		It("writes the files before the run", func() {
+			result, err := runner.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Stdout).To(Equal("hello john\n"))
+		})
+	})
+
+	Describe("AssertGolden", func() {
+		var t *recorder
+
+		BeforeEach(func() {
+			t = &recorder{}
+		})
+
+		It("matches the help output", func() {
+			result, err := runner.Run("--help")
+			Expect(err).NotTo(HaveOccurred())
+
+			result.AssertGolden(GinkgoT(), filepath.Join("testdata", "help.golden"))
+		})
+
+		It("reports the difference", func() {
+			result := &clitest.Result{Stdout: "hi world\n"}
+			result.AssertGolden(t, filepath.Join("testdata", "help.golden"))
+			Expect(t.errors).To(HaveLen(1))
+			Expect(t.errors[0]).To(ContainSubstring("does not match the golden file"))
+		})
+
+		Context("when the golden file does not exist", func() {
+			It("fails", func() {
+				result := &clitest.Result{}
+				result.AssertGolden(t, filepath.Join("testdata", "unknown.golden"))
+				Expect(t.errors).To(HaveLen(1))
+				Expect(t.errors[0]).To(ContainSubstring("cannot read the golden file"))
+			})
+		})
+	})
+})
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
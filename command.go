@@ -2,7 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff"
@@ -15,7 +18,8 @@ import (
 type Command struct {
 	// The name of the command
 	Name string
-	// A list of aliases for the command
+	// A list of aliases for the command. Deprecated aliases are prefixed with
+	// DeprecatedPrefix
 	Aliases []string
 	// A short description of the usage of this command
 	Usage string
@@ -29,6 +33,9 @@ type Command struct {
 	Category string
 	// Boolean to hide this command from help or completion
 	Hidden bool
+	// Deprecated is a message shown when the command is used. Deprecated
+	// commands are hidden from help unless --help-all is passed
+	Deprecated string
 	// Full name of command for help, defaults to full command name, including parent commands.
 	HelpName string
 	// Boolean to hide built-in help command
@@ -39,10 +46,34 @@ type Command struct {
 	Commands []*Command
 	// Treat all flags as normal arguments if true
 	SkipFlagParsing bool
+	// AllowPrefixMatching resolves a command by an unambiguous prefix of its
+	// name. It is inherited by all child commands
+	AllowPrefixMatching bool
+	// Interactive prompts for the missing required flags when the input is a
+	// terminal. It is inherited by all child commands
+	Interactive bool
+	// Interpolate expands the references to the environment variables and to
+	// the other flags in the values and the paths of the flags, e.g.
+	// ${HOME}/.app. It is inherited by all child commands
+	Interpolate bool
+	// HelpWidth is the width the help is wrapped to. It defaults to the
+	// terminal width and a negative value turns the wrapping off. It is
+	// inherited by all child commands
+	HelpWidth int
+	// HelpTemplate replaces the embedded template of the command help
+	HelpTemplate string
+	// TemplateFuncs are merged into the functions of the help templates. It
+	// is inherited by the child commands that do not define their own
+	TemplateFuncs map[string]interface{}
 	// List of flags to parse
 	Flags []Flag
 	// Providers contains a list of all providers
 	Providers []Provider
+	// Precedence replaces the built-in providers. They are applied in order,
+	// so a later provider overrides the values set by an earlier one. A non
+	// nil empty list turns the built-in providers off. It is inherited by the
+	// child commands that do not define their own
+	Precedence []Provider
 	// An action to execute before any subcommands are run, but after the context is ready
 	// If a non-nil error is returned, no subcommands are run
 	Before BeforeFunc
@@ -55,12 +86,35 @@ type Command struct {
 	// The action to execute when no subcommands are specified
 	// Expects a cli.ActionFunc
 	Action ActionFunc
+	// Middleware wraps the action in order, the first one is the outermost.
+	// The middleware of the parent commands wraps the middleware of the child
+	// commands
+	Middleware []MiddlewareFunc
 	// Strategy enables comman retry logic
 	Strategy BackOffStrategy
 	// Execute this function if a usage error occurs.
 	OnUsageError UsageErrorFunc
 	// OnCommandNotFound is executed if the proper command cannot be found
 	OnCommandNotFound CommandNotFoundFunc
+	// OnReload is executed when the values of the flags read from files are
+	// reloaded while the action runs. The files are checked at the
+	// ReloadInterval and when one of the ReloadSignals is received. They are
+	// inherited by all child commands
+	OnReload ReloadFunc
+	// ReloadInterval is the interval the files are polled at. Zero turns the
+	// polling off
+	ReloadInterval time.Duration
+	// ReloadSignals are the signals that reload the files, e.g. SIGHUP
+	ReloadSignals []os.Signal
+	// Plugins adds the executables named after the command and a
+	// subcommand, e.g. app-deploy, as child commands. It is inherited by all
+	// child commands
+	Plugins bool
+	// PluginDirs are searched for the plugins before the PATH
+	PluginDirs []string
+	// AliasPath is the path of the YAML file with the user-defined aliases
+	// of the subcommands, e.g. ${HOME}/.config/app/config.yaml
+	AliasPath string
 }
 
 // NewHelpCommand creates a new help command
@@ -68,7 +122,7 @@ func NewHelpCommand() *Command {
 	help := &Command{
 		Name:            "help",
 		Aliases:         []string{"h"},
-		Usage:           "Shows a list of commands or help for one command",
+		Usage:           "command.help",
 		ArgsUsage:       "[command]",
 		HideHelp:        true,
 		SkipFlagParsing: true,
@@ -83,7 +137,7 @@ func NewVersionCommand() *Command {
 	return &Command{
 		Name:            "version",
 		Aliases:         []string{"v"},
-		Usage:           "Prints the version",
+		Usage:           "command.version",
 		ArgsUsage:       "[command]",
 		HideHelp:        true,
 		Hidden:          true,
@@ -93,8 +147,33 @@ func NewVersionCommand() *Command {
 }
 
 // RunWithContext runs the command
-func (cmd *Command) RunWithContext(ctx *Context) error {
-	cmd.prepare()
+func (cmd *Command) RunWithContext(ctx *Context) (errx error) {
+	cmd = cmd.resolve()
+	ctx.Command = cmd
+
+	if ctx.current != nil {
+		ctx.current.Store(ctx)
+	}
+
+	if ctx.services == nil {
+		ctx.services = &registry{}
+	}
+
+	if ctx.values == nil {
+		ctx.values = &sync.RWMutex{}
+	}
+
+	// the services are closed after the After hooks have run
+	defer func() {
+		if err := ctx.services.close(ctx); err != nil {
+			if errx != nil {
+				ctx.warn("warning.services", err)
+				return
+			}
+
+			errx = WrapError(err)
+		}
+	}()
 
 	if err := cmd.provide(ctx); err != nil {
 		return cmd.error(ctx, err)
@@ -104,27 +183,65 @@ func (cmd *Command) RunWithContext(ctx *Context) error {
 
 	if errx, ok := err.(ExitCoder); ok {
 		if errx.Code() == ExitCodeNotFoundCommand {
-			return cmd.exec(cmd.Action, ctx)
+			// the configuration is printed instead of running the action
+			if ctx.printConfig() {
+				return config(ctx)
+			}
+
+			// the missing values are prompted only when the action runs
+			if err := cmd.prompt(ctx); err != nil {
+				return cmd.error(ctx, err)
+			}
+
+			return cmd.exec(cmd.reload(cmd.chain(cmd.Action)), ctx)
 		}
 	}
 
 	return err
 }
 
-// Names returns the names including short names and aliases.
+// Use adds middleware that wraps the action of the command and its child
+// commands
+func (cmd *Command) Use(middleware ...MiddlewareFunc) {
+	cmd.Middleware = append(cmd.Middleware, middleware...)
+}
+
+// chain wraps the action with the middleware
+func (cmd *Command) chain(action ActionFunc) ActionFunc {
+	if action == nil {
+		return nil
+	}
+
+	for index := len(cmd.Middleware) - 1; index >= 0; index-- {
+		action = cmd.Middleware[index](action)
+	}
+
+	return action
+}
+
+// Names returns the names including short names and aliases that are not
+// deprecated.
 func (cmd *Command) Names() []string {
 	names := []string{cmd.Name}
-	return append(names, cmd.Aliases...)
+
+	for _, alias := range cmd.Aliases {
+		if !strings.HasPrefix(alias, DeprecatedPrefix) {
+			names = append(names, alias)
+		}
+	}
+
+	return names
 }
 
-// VisibleFlags returns a slice of the Flags with Hidden=false
+// VisibleFlags returns a slice of the Flags with Hidden=false that are not
+// deprecated
 func (cmd *Command) VisibleFlags() []Flag {
 	flags := []Flag{}
 
 	for _, flag := range cmd.Flags {
 		accessor := NewFlagAccessor(flag)
 
-		if accessor.Hidden() {
+		if accessor.Hidden() || accessor.Deprecated() != "" {
 			continue
 		}
 
@@ -134,6 +251,50 @@ func (cmd *Command) VisibleFlags() []Flag {
 	return flags
 }
 
+// DeprecatedItems returns the deprecated flags, commands and aliases
+// formatted for the help output
+func (cmd *Command) DeprecatedItems() []string {
+	items := []string{}
+
+	for _, flag := range cmd.Flags {
+		accessor := NewFlagAccessor(flag)
+
+		if accessor.Hidden() {
+			continue
+		}
+
+		if accessor.Deprecated() != "" {
+			items = append(items, accessor.String())
+			continue
+		}
+
+		for _, name := range deprecated(accessor.Name()) {
+			item := fmt.Sprintf("%s\t(deprecated: use %s instead)", dashed(name), dashed(primary(accessor.Name())))
+			items = append(items, item)
+		}
+	}
+
+	for _, command := range cmd.Commands {
+		if command.Hidden {
+			continue
+		}
+
+		if command.Deprecated != "" {
+			usage := strings.TrimSpace(fmt.Sprintf("%s (deprecated: %s)", command.Usage, command.Deprecated))
+			item := fmt.Sprintf("%s\t%s", strings.Join(command.Names(), ", "), usage)
+			items = append(items, item)
+			continue
+		}
+
+		for _, name := range deprecated(strings.Join(command.Aliases, ",")) {
+			item := fmt.Sprintf("%s\t(deprecated: use %s instead)", name, command.Name)
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
 // VisibleCommands returns a slice of the Commands with Hidden=false
 func (cmd *Command) VisibleCommands() []*Command {
 	category := &CommandCategory{
@@ -194,15 +355,81 @@ func (cmd *Command) provide(ctx *Context) (errx error) {
 	}
 
 	for _, provider := range cmd.Providers {
+		snapshot := cmd.snapshot(ctx)
+
 		if err := cmd.retry(provider.Provide, ctx); err != nil {
 			errs.Wrap(err)
 			return
 		}
+
+		cmd.detect(ctx, provider, snapshot)
 	}
 
+	if err := cmd.interpolate(ctx); err != nil {
+		errs.Wrap(err)
+		return
+	}
+
+	cmd.deprecations(ctx)
 	return
 }
 
+// deprecations warns about the deprecated flags and environment variables
+// that have been used
+func (cmd *Command) deprecations(ctx *Context) {
+	for _, flag := range cmd.Flags {
+		var (
+			accessor = NewFlagAccessor(flag)
+			source   = ctx.source(flag)
+		)
+
+		switch {
+		case source.Kind == SourceDefault:
+			continue
+		case accessor.Deprecated() != "":
+			ctx.warn("warning.flag_deprecated", primary(accessor.Name()), source, accessor.Deprecated())
+		case source.Kind == SourceFlag && contains(deprecated(accessor.Name()), source.Name):
+			ctx.warn("warning.flag_renamed", dashed(source.Name), dashed(primary(accessor.Name())))
+		case source.Kind == SourceEnv && contains(deprecated(accessor.EnvVar()), source.Name):
+			ctx.warn("warning.env_renamed", source.Name, primary(accessor.EnvVar()))
+		}
+	}
+}
+
+type flagState struct {
+	value  string
+	source *Source
+}
+
+func (cmd *Command) snapshot(ctx *Context) map[Flag]flagState {
+	states := make(map[Flag]flagState)
+
+	for _, flag := range cmd.Flags {
+		states[flag] = flagState{
+			value:  fmt.Sprintf("%v", flag.Get()),
+			source: ctx.sources[flag],
+		}
+	}
+
+	return states
+}
+
+// detect tracks the flags changed by providers that do not report the source
+// of the values by themselves
+func (cmd *Command) detect(ctx *Context, provider Provider, states map[Flag]flagState) {
+	for _, flag := range cmd.Flags {
+		state := states[flag]
+
+		if state.source != ctx.sources[flag] {
+			continue
+		}
+
+		if state.value != fmt.Sprintf("%v", flag.Get()) {
+			ctx.track(flag, SourceProvider, fmt.Sprintf("%T", provider))
+		}
+	}
+}
+
 func (cmd *Command) validate(ctx *Context) error {
 	for _, flag := range cmd.Flags {
 		accessor := NewFlagAccessor(flag)
@@ -214,25 +441,24 @@ func (cmd *Command) validate(ctx *Context) error {
 	return nil
 }
 
-func (cmd *Command) prepare() {
-	cmd.providers()
-	cmd.flags()
-	cmd.commands()
+// resolve returns a copy of the command with the built-in providers, flags
+// and commands. The command is not changed, so it can run more than once.
+func (cmd *Command) resolve() *Command {
+	resolved := *cmd
+	resolved.providers()
+	resolved.flags()
+	resolved.commands()
+	return &resolved
 }
 
 func (cmd *Command) providers() {
-	providers := []Provider{
-		&PathProvider{
-			IsPathFlag: false,
-		},
-		&EnvProvider{},
-		&FlagProvider{},
-		&PathProvider{
-			IsPathFlag: true,
-		},
+	providers := cmd.Precedence
+
+	if providers == nil {
+		providers = DefaultProviders()
 	}
 
-	cmd.Providers = append(providers, cmd.Providers...)
+	cmd.Providers = append(append([]Provider{}, providers...), cmd.Providers...)
 }
 
 func (cmd *Command) commands() {
@@ -240,51 +466,216 @@ func (cmd *Command) commands() {
 		cmd.HelpName = cmd.Name
 	}
 
+	commands := make([]*Command, 0, len(cmd.Commands)+1)
+
+	// the inherited settings are applied to copies of the child commands
+	for _, command := range cmd.Commands {
+		child := *command
+		commands = append(commands, &child)
+	}
+
 	if !cmd.HideHelp {
-		help := NewHelpCommand()
-		cmd.Commands = append(cmd.Commands, help)
+		commands = append(commands, NewHelpCommand())
 	}
 
+	cmd.Commands = commands
+
 	for _, command := range cmd.Commands {
 		if command.HelpName == "" {
 			command.HelpName = fmt.Sprintf("%s %s", cmd.HelpName, command.Name)
 		}
+
+		if cmd.AllowPrefixMatching {
+			command.AllowPrefixMatching = true
+		}
+
+		if cmd.Interactive {
+			command.Interactive = true
+		}
+
+		if cmd.Interpolate {
+			command.Interpolate = true
+		}
+
+		if command.HelpWidth == 0 {
+			command.HelpWidth = cmd.HelpWidth
+		}
+
+		if command.TemplateFuncs == nil {
+			command.TemplateFuncs = cmd.TemplateFuncs
+		}
+
+		if command.Precedence == nil {
+			command.Precedence = cmd.Precedence
+		}
+
+		// the built-in commands are not wrapped
+		if len(cmd.Middleware) > 0 && command.Name != "help" && command.Name != "version" {
+			command.Middleware = append(append([]MiddlewareFunc{}, cmd.Middleware...), command.Middleware...)
+		}
+
+		if cmd.Plugins && !command.Plugins {
+			command.Plugins = true
+			command.PluginDirs = cmd.PluginDirs
+		}
+
+		if command.OnReload == nil {
+			command.OnReload = cmd.OnReload
+			command.ReloadInterval = cmd.ReloadInterval
+			command.ReloadSignals = cmd.ReloadSignals
+		}
 	}
 }
 
 func (cmd *Command) flags() {
+	flags := make([]Flag, 0, len(cmd.Flags)+2)
+
+	// the values parsed by a run must not leak into the next one
+	for _, flag := range cmd.Flags {
+		flags = append(flags, duplicate(flag))
+	}
+
+	cmd.Flags = flags
+
 	if !cmd.HideHelp {
 		help := &BoolFlag{
 			Name:  "help, h",
-			Usage: "shows help",
+			Usage: "flag.help",
+		}
+
+		// help-all is visible only if there is something deprecated to show
+		all := &BoolFlag{
+			Name:   "help-all",
+			Usage:  "flag.help_all",
+			Hidden: len(cmd.DeprecatedItems()) == 0,
 		}
 
-		cmd.Flags = append(cmd.Flags, help)
+		cmd.Flags = append(cmd.Flags, help, all)
 	}
 
-	if cmd.Metadata == nil {
-		cmd.Metadata = make(map[string]interface{})
+	metadata := make(map[string]interface{}, len(cmd.Metadata)+1)
+
+	for key, value := range cmd.Metadata {
+		metadata[key] = value
 	}
 
+	cmd.Metadata = metadata
 	cmd.Metadata["VisibleFlags"] = cmd.VisibleFlags()
 }
 
+// duplicate creates a copy of the flag. Its value is copied too, so the
+// documents and the slices are not shared between the runs. The custom flags
+// that are not pointers are returned as they are.
+func duplicate(flag Flag) Flag {
+	source := reflect.ValueOf(flag)
+
+	if source.Kind() != reflect.Ptr || source.IsNil() {
+		return flag
+	}
+
+	target := reflect.New(source.Elem().Type())
+	target.Elem().Set(source.Elem())
+
+	if value := target.Elem().FieldByName("Value"); value.IsValid() && value.CanSet() {
+		value.Set(fresh(value))
+	}
+
+	return target.Interface().(Flag)
+}
+
+// fresh returns a copy of the value that can be decoded into without changing
+// the original. The decoders set only the exported fields of the structs, so
+// the unexported ones are shared.
+func fresh(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+
+		target := reflect.New(value.Type().Elem())
+		target.Elem().Set(fresh(value.Elem()))
+		return target
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+
+		target := reflect.New(value.Type()).Elem()
+		target.Set(fresh(value.Elem()))
+		return target
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		target := reflect.MakeMapWithSize(value.Type(), value.Len())
+
+		for iter := value.MapRange(); iter.Next(); {
+			target.SetMapIndex(iter.Key(), fresh(iter.Value()))
+		}
+
+		return target
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		target := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+
+		for index := 0; index < value.Len(); index++ {
+			target.Index(index).Set(fresh(value.Index(index)))
+		}
+
+		return target
+	case reflect.Array:
+		target := reflect.New(value.Type()).Elem()
+
+		for index := 0; index < value.Len(); index++ {
+			target.Index(index).Set(fresh(value.Index(index)))
+		}
+
+		return target
+	case reflect.Struct:
+		target := reflect.New(value.Type()).Elem()
+		target.Set(value)
+
+		for index := 0; index < value.NumField(); index++ {
+			if field := target.Field(index); field.CanSet() {
+				field.Set(fresh(value.Field(index)))
+			}
+		}
+
+		return target
+	default:
+		return value
+	}
+}
+
 func (cmd *Command) fork(ctx *Context) error {
 	var (
 		child *Command
 		name  string
 		args  []string
+		err   error
 	)
 
+	if ctx.Args, err = cmd.unalias(ctx, ctx.Args); err != nil {
+		return err
+	}
+
 	switch {
-	case ctx.Bool("help"):
+	case ctx.Bool("help"), ctx.Bool("help-all"):
 		child = cmd.find("help")
 	case ctx.Bool("version"):
 		child = cmd.find("version")
 	case len(ctx.Args) > 0:
 		name = ctx.Args[0]
-		child, args = cmd.next(ctx.Args)
-	case cmd.Action == nil:
+
+		if child, args, err = cmd.next(ctx, ctx.Args); err != nil {
+			return err
+		}
+	case cmd.Action == nil && !ctx.printConfig():
 		child = cmd.find("help")
 	}
 
@@ -296,28 +687,54 @@ func (cmd *Command) fork(ctx *Context) error {
 		return NotFoundCommandError(name)
 	}
 
-	ctx = &Context{
+	switch {
+	case child.Deprecated != "":
+		ctx.warn("warning.command_deprecated", child.Name, child.Deprecated)
+	case contains(deprecated(strings.Join(child.Aliases, ",")), name):
+		ctx.warn("warning.command_renamed", name, child.Name)
+	}
+
+	if child.Name == "help" || child.Name == "version" {
+		return child.RunWithContext(ctx.child(child, args))
+	}
+
+	if err := cmd.prompt(ctx); err != nil {
+		return cmd.error(ctx, err)
+	}
+
+	return cmd.exec(child.RunWithContext, ctx.child(child, args))
+}
+
+// child creates the context of a child command
+func (ctx *Context) child(command *Command, args []string) *Context {
+	return &Context{
 		Parent:    ctx,
 		Metadata:  ctx.Metadata,
+		Reader:    ctx.Reader,
 		Writer:    ctx.Writer,
 		ErrWriter: ctx.ErrWriter,
-		Command:   child,
+		Terminal:  ctx.Terminal,
+		LookupEnv: ctx.LookupEnv,
+		Environ:   ctx.Environ,
+		Locale:    ctx.Locale,
+		current:   ctx.current,
+		services:  ctx.services,
+		values:    ctx.values,
+		router:    ctx.router,
+		Command:   command,
 		Args:      args,
 	}
-
-	if child.Name != "help" && child.Name != "version" {
-		return cmd.exec(child.RunWithContext, ctx)
-	}
-
-	return child.RunWithContext(ctx)
 }
 
-func (cmd *Command) next(args []string) (*Command, []string) {
-	child := cmd.find(args[0])
+func (cmd *Command) next(ctx *Context, args []string) (*Command, []string, error) {
+	child, err := cmd.match(ctx, args[0])
+	if err != nil {
+		return nil, nil, err
+	}
 
 	if child == nil {
 		if !cmd.has() {
-			return nil, []string{}
+			return nil, []string{}, nil
 		}
 
 		child = cmd.find("help")
@@ -326,12 +743,62 @@ func (cmd *Command) next(args []string) (*Command, []string) {
 		args = args[1:]
 	}
 
-	return child, args
+	return child, args, nil
+}
+
+// match finds a command by its name, or by an unambiguous prefix of the name
+// when the prefix matching is allowed. The plugins are searched only when no
+// command has the name.
+func (cmd *Command) match(ctx *Context, name string) (*Command, error) {
+	if child := cmd.find(name); child != nil {
+		return child, nil
+	}
+
+	if child := cmd.lookup(ctx, name); child != nil {
+		return child, nil
+	}
+
+	if !cmd.AllowPrefixMatching || name == "" {
+		return nil, nil
+	}
+
+	// the plugins can match the prefix too
+	cmd.plugins(ctx)
+
+	var (
+		children   []*Command
+		candidates []string
+	)
+
+	for _, child := range cmd.VisibleCommands() {
+		for _, alias := range child.Names() {
+			if !hasPrefixFold(alias, name) {
+				continue
+			}
+
+			candidates = append(candidates, alias)
+
+			if len(children) == 0 || children[len(children)-1] != child {
+				children = append(children, child)
+			}
+		}
+	}
+
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	default:
+		return nil, AmbiguousCommandError(name, candidates)
+	}
 }
 
 func (cmd *Command) find(name string) *Command {
 	for _, child := range cmd.Commands {
-		for _, alias := range child.Names() {
+		aliases := append([]string{child.Name}, child.Aliases...)
+
+		for _, alias := range names(strings.Join(aliases, ",")) {
 			if strings.EqualFold(alias, name) {
 				return child
 			}
@@ -431,7 +898,7 @@ func (cmd *Command) error(ctx *Context, err error) error {
 		return nil
 	}
 
-	fmt.Fprintln(ctx.Writer, "Incorrect Usage:", err.Error())
+	fmt.Fprintln(ctx.Writer, ctx.Translate("help.incorrect"), localize(ctx.locale(), err))
 	fmt.Fprintln(ctx.Writer)
 
 	ctx.Args = []string{"help"}
@@ -465,12 +932,13 @@ type CommandCategory struct {
 	Commands []*Command
 }
 
-// VisibleCommands returns a slice of the Commands with Hidden=false
+// VisibleCommands returns a slice of the Commands with Hidden=false that are
+// not deprecated
 func (category *CommandCategory) VisibleCommands() []*Command {
 	items := []*Command{}
 
 	for _, command := range category.Commands {
-		if !command.Hidden {
+		if !command.Hidden && command.Deprecated == "" {
 			items = append(items, command)
 		}
 	}
@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// the key of the aliases in the alias file
+const aliasKey = "aliases"
+
+// NewAliasCommand creates the alias command with the list, add and remove
+// subcommands that manage the user-defined aliases
+func NewAliasCommand() *Command {
+	return &Command{
+		Name:  "alias",
+		Usage: "command.alias",
+		Commands: []*Command{
+			&Command{
+				Name:   "list",
+				Usage:  "command.alias_list",
+				Action: aliasList,
+			},
+			&Command{
+				Name:            "add",
+				Usage:           "command.alias_add",
+				ArgsUsage:       "<name> <command>...",
+				SkipFlagParsing: true,
+				Action:          aliasAdd,
+			},
+			&Command{
+				Name:      "remove",
+				Aliases:   []string{"rm"},
+				Usage:     "command.alias_remove",
+				ArgsUsage: "<name>",
+				Action:    aliasRemove,
+			},
+		},
+	}
+}
+
+// Alias is a user-defined name for a command line
+type Alias struct {
+	// Name of the alias
+	Name string `json:"name" yaml:"name" xml:"name"`
+	// Command is the command line that replaces the alias
+	Command string `json:"command" yaml:"command" xml:"command"`
+}
+
+// unalias replaces the user-defined alias in the arguments with its command
+// line. The commands take precedence over the aliases.
+func (cmd *Command) unalias(ctx *Context, args []string) ([]string, error) {
+	if cmd.AliasPath == "" || len(args) == 0 {
+		return args, nil
+	}
+
+	_, aliases, err := ctx.aliases()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		name  = args[0]
+		chain = []string{}
+	)
+
+	for len(args) > 0 {
+		command, ok := aliases[args[0]]
+
+		if !ok || cmd.find(args[0]) != nil {
+			return args, nil
+		}
+
+		if contains(chain, args[0]) {
+			chain = append(chain, args[0])
+			return nil, AliasError(name, newError(ExitCodeErrorAlias, "error.alias_loop", strings.Join(chain, " -> ")))
+		}
+
+		chain = append(chain, args[0])
+
+		items, err := words(command)
+		if err != nil {
+			return nil, AliasError(name, err)
+		}
+
+		args = append(items, args[1:]...)
+	}
+
+	return args, nil
+}
+
+// aliases reads the alias file of the root command. A missing file has no
+// aliases.
+func (ctx *Context) aliases() (yaml.MapSlice, map[string]string, error) {
+	path, err := ctx.aliasPath()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	document := yaml.MapSlice{}
+	aliases := map[string]string{}
+
+	data, err := os.ReadFile(path)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return document, aliases, nil
+	case err != nil:
+		return nil, nil, err
+	}
+
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for _, item := range document {
+		if item.Key != aliasKey {
+			continue
+		}
+
+		items, ok := item.Value.(yaml.MapSlice)
+		if !ok && item.Value != nil {
+			return nil, nil, newError(ExitCodeErrorAlias, "error.alias_map", path, aliasKey)
+		}
+
+		for _, alias := range items {
+			aliases[fmt.Sprint(alias.Key)] = fmt.Sprint(alias.Value)
+		}
+	}
+
+	return document, aliases, nil
+}
+
+// save writes the aliases to the alias file. The other keys of the file are
+// kept.
+func (ctx *Context) save(document yaml.MapSlice, aliases map[string]string) error {
+	path, err := ctx.aliasPath()
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+
+	for name := range aliases {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	items := yaml.MapSlice{}
+
+	for _, name := range names {
+		items = append(items, yaml.MapItem{Key: name, Value: aliases[name]})
+	}
+
+	found := false
+
+	for index, item := range document {
+		if item.Key == aliasKey {
+			document[index].Value = items
+			found = true
+		}
+	}
+
+	if !found {
+		document = append(document, yaml.MapItem{Key: aliasKey, Value: items})
+	}
+
+	data, err := yaml.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// aliasPath returns the expanded path of the alias file of the closest
+// command that has one
+func (ctx *Context) aliasPath() (string, error) {
+	for current := ctx; current != nil; current = current.Parent {
+		if path := current.Command.AliasPath; path != "" {
+			return current.expand(path)
+		}
+	}
+
+	return "", newError(ExitCodeErrorAlias, "error.alias_path")
+}
+
+// aliasRoot returns the command that expands the aliases
+func (ctx *Context) aliasRoot() *Command {
+	for current := ctx; current != nil; current = current.Parent {
+		if current.Command.AliasPath != "" {
+			return current.Command
+		}
+	}
+
+	return nil
+}
+
+func aliasList(ctx *Context) error {
+	_, aliases, err := ctx.aliases()
+	if err != nil {
+		return err
+	}
+
+	items := []*Alias{}
+
+	for name, command := range aliases {
+		items = append(items, &Alias{Name: name, Command: command})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return ctx.Render(items)
+}
+
+func aliasAdd(ctx *Context) error {
+	if len(ctx.Args) < 2 {
+		return newError(ExitCodeErrorAlias, "error.alias_add")
+	}
+
+	name := ctx.Args[0]
+
+	if strings.ContainsAny(name, " \t\n'\"\\") || strings.HasPrefix(name, "-") {
+		return AliasError(name, newError(ExitCodeErrorAlias, "error.alias_name"))
+	}
+
+	// the aliases must not shadow the commands
+	if root := ctx.aliasRoot(); root != nil && root.find(name) != nil {
+		return AliasError(name, newError(ExitCodeErrorAlias, "error.alias_command"))
+	}
+
+	command := ctx.Args[1]
+
+	if len(ctx.Args) > 2 {
+		items := make([]string, len(ctx.Args)-1)
+
+		for index, arg := range ctx.Args[1:] {
+			items[index] = quote(arg)
+		}
+
+		command = strings.Join(items, " ")
+	}
+
+	if _, err := words(command); err != nil {
+		return AliasError(name, err)
+	}
+
+	document, aliases, err := ctx.aliases()
+	if err != nil {
+		return err
+	}
+
+	aliases[name] = command
+	return ctx.save(document, aliases)
+}
+
+func aliasRemove(ctx *Context) error {
+	if len(ctx.Args) != 1 {
+		return newError(ExitCodeErrorAlias, "error.alias_remove")
+	}
+
+	document, aliases, err := ctx.aliases()
+	if err != nil {
+		return err
+	}
+
+	name := ctx.Args[0]
+
+	if _, ok := aliases[name]; !ok {
+		return AliasError(name, newError(ExitCodeErrorAlias, "error.alias_missing"))
+	}
+
+	delete(aliases, name)
+	return ctx.save(document, aliases)
+}
+
+// words splits a command line like a shell. The single quotes keep the text
+// as it is, the double quotes and the backslash escape the next character.
+func words(text string) ([]string, error) {
+	var (
+		items   = []string{}
+		word    = &strings.Builder{}
+		inWord  = false
+		quote   rune
+		escaped = false
+	)
+
+	for _, char := range text {
+		switch {
+		case escaped:
+			word.WriteRune(char)
+			escaped = false
+		case quote == '\'':
+			if char == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(char)
+			}
+		case char == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if char == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(char)
+			}
+		case char == '\'' || char == '"':
+			quote = char
+			inWord = true
+		case char == ' ' || char == '\t' || char == '\n':
+			if inWord {
+				items = append(items, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(char)
+			inWord = true
+		}
+	}
+
+	switch {
+	case escaped:
+		return nil, newError(ExitCodeErrorApp, "error.backslash", text)
+	case quote != 0:
+		return nil, newError(ExitCodeErrorApp, "error.quote", quote, text)
+	}
+
+	if inWord {
+		items = append(items, word.String())
+	}
+
+	return items, nil
+}
+
+// quote quotes a word, so it is kept by words
+func quote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\n'\"\\") {
+		return word
+	}
+
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
@@ -0,0 +1,162 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Alias", func() {
+	var (
+		cmd    *cli.Command
+		ctx    *cli.Context
+		path   string
+		args   []string
+		output *bytes.Buffer
+	)
+
+	write := func(content string) {
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	}
+
+	read := func() string {
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		args = nil
+		output = &bytes.Buffer{}
+
+		cmd = &cli.Command{
+			Name:      "app",
+			AliasPath: path,
+			Commands: []*cli.Command{
+				&cli.Command{
+					Name: "deploy",
+					Commands: []*cli.Command{
+						&cli.Command{
+							Name: "list",
+							Flags: []cli.Flag{
+								&cli.StringFlag{Name: "env"},
+							},
+							Action: func(ctx *cli.Context) error {
+								args = append([]string{ctx.String("env")}, ctx.Args...)
+								return nil
+							},
+						},
+					},
+				},
+				cli.NewAliasCommand(),
+			},
+		}
+
+		ctx = &cli.Context{
+			Command:   cmd,
+			Writer:    output,
+			ErrWriter: output,
+		}
+	})
+
+	It("expands the alias", func() {
+		write("aliases:\n  dl: deploy list --env 'prod eu'\n")
+		ctx.Args = []string{"dl", "web"}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(args).To(Equal([]string{"prod eu", "web"}))
+	})
+
+	It("expands the nested aliases", func() {
+		write("aliases:\n  dl: deploy list\n  prod: dl --env prod\n")
+		ctx.Args = []string{"prod"}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(args).To(Equal([]string{"prod"}))
+	})
+
+	Context("when the alias has the name of a command", func() {
+		It("runs the command", func() {
+			write("aliases:\n  deploy: deploy list --env prod\n")
+			ctx.Args = []string{"deploy", "list"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(args).To(Equal([]string{""}))
+		})
+	})
+
+	Context("when the aliases form a loop", func() {
+		It("returns an error", func() {
+			write("aliases:\n  a: b --env prod\n  b: a\n")
+			ctx.Args = []string{"a"}
+
+			err := cmd.RunWithContext(ctx)
+			Expect(err).To(MatchError("alias 'a': alias loop: a -> b -> a"))
+
+			errx, ok := err.(cli.ExitCoder)
+			Expect(ok).To(BeTrue())
+			Expect(errx.Code()).To(Equal(cli.ExitCodeErrorAlias))
+		})
+	})
+
+	Context("when the alias is not closed", func() {
+		It("returns an error", func() {
+			write("aliases:\n  dl: deploy list --env 'prod\n")
+			ctx.Args = []string{"dl"}
+
+			Expect(cmd.RunWithContext(ctx)).To(MatchError(`alias 'dl': missing ' in "deploy list --env 'prod"`))
+		})
+	})
+
+	Describe("alias command", func() {
+		It("adds an alias", func() {
+			write("color: never\n")
+			ctx.Args = []string{"alias", "add", "dl", "deploy", "list", "--env", "prod eu"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(read()).To(Equal("color: never\naliases:\n  dl: deploy list --env 'prod eu'\n"))
+
+			ctx.Args = []string{"dl"}
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(args).To(Equal([]string{"prod eu"}))
+		})
+
+		It("lists the aliases", func() {
+			write("aliases:\n  dl: deploy list\n  a: deploy\n")
+			ctx.Args = []string{"alias", "list"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(output.String()).To(Equal("NAME  COMMAND\na     deploy\ndl    deploy list\n"))
+		})
+
+		It("removes an alias", func() {
+			write("aliases:\n  dl: deploy list\n  a: deploy\n")
+			ctx.Args = []string{"alias", "rm", "dl"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(read()).To(Equal("aliases:\n  a: deploy\n"))
+		})
+
+		Context("when the name is used by a command", func() {
+			It("returns an error", func() {
+				ctx.Args = []string{"alias", "add", "deploy", "deploy", "list"}
+
+				Expect(cmd.RunWithContext(ctx)).To(MatchError("alias 'deploy': the name is used by a command"))
+			})
+		})
+
+		Context("when the alias does not exist", func() {
+			It("returns an error", func() {
+				ctx.Args = []string{"alias", "remove", "dl"}
+
+				Expect(cmd.RunWithContext(ctx)).To(MatchError("alias 'dl': not found"))
+			})
+		})
+	})
+})
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the value shown instead of a secret
+const secretMask = "******"
+
+// the built-in flags are not part of the configuration
+var configIgnored = []string{"help", "help-all", "version", "print-config"}
+
+// NewPrintConfigFlag creates the --print-config flag that prints the resolved
+// configuration instead of running the command
+func NewPrintConfigFlag() *BoolFlag {
+	return &BoolFlag{
+		Name:  "print-config",
+		Usage: "flag.print_config",
+	}
+}
+
+// NewConfigCommand creates the config command with the show subcommand that
+// prints the resolved configuration
+func NewConfigCommand() *Command {
+	return &Command{
+		Name:  "config",
+		Usage: "command.config",
+		Commands: []*Command{
+			&Command{
+				Name:   "show",
+				Usage:  "command.config_show",
+				Action: config,
+			},
+		},
+	}
+}
+
+// Config is the resolved configuration of a command
+type Config struct {
+	// Precedence contains the layers that were applied to the flags from the
+	// lowest to the highest priority
+	Precedence []string `json:"precedence" yaml:"precedence" xml:"precedence>layer"`
+	// Values contains the values of the flags of the command and its parents
+	Values []*ConfigValue `json:"values" yaml:"values" xml:"values>value"`
+}
+
+// ConfigValue is the value of a flag and its origin
+type ConfigValue struct {
+	// Command is the full name of the command that defines the flag
+	Command string `json:"command" yaml:"command" xml:"command"`
+	// Flag is the name of the flag
+	Flag string `json:"flag" yaml:"flag" xml:"flag"`
+	// Value of the flag. The value of a secret is masked
+	Value string `json:"value" yaml:"value" xml:"value"`
+	// Source is the origin of the value
+	Source string `json:"source" yaml:"source" xml:"source"`
+}
+
+// Config returns the resolved configuration of the command and its parents
+func (ctx *Context) Config() *Config {
+	var (
+		chain  []*Context
+		config = &Config{}
+	)
+
+	for current := ctx; current != nil; current = current.Parent {
+		chain = append([]*Context{current}, chain...)
+	}
+
+	layers := []string{string(SourceDefault)}
+
+	for _, current := range chain {
+		cmd := current.Command
+
+		for _, provider := range cmd.Providers {
+			layers = append(layers, layer(provider))
+		}
+
+		if cmd.Interactive {
+			layers = append(layers, string(SourcePrompt))
+		}
+
+		for _, flag := range cmd.Flags {
+			accessor := NewFlagAccessor(flag)
+			name := primary(accessor.Name())
+
+			if contains(configIgnored, name) {
+				continue
+			}
+
+			value := toString(accessor.Value())
+
+			// secrets are never exposed in plain text
+			if accessor.IsSecretFlag() && value != "" {
+				value = secretMask
+			}
+
+			config.Values = append(config.Values, &ConfigValue{
+				Command: cmd.HelpName,
+				Flag:    name,
+				Value:   value,
+				Source:  current.source(flag).String(),
+			})
+		}
+	}
+
+	// every layer is listed once at its first position
+	for _, name := range layers {
+		if !contains(config.Precedence, name) {
+			config.Precedence = append(config.Precedence, name)
+		}
+	}
+
+	return config
+}
+
+// layer returns the name of the configuration layer of a provider
+func layer(provider Provider) string {
+	switch p := provider.(type) {
+	case *PathProvider:
+		if p.IsPathFlag {
+			return "document"
+		}
+
+		return string(SourcePath)
+	case *EnvProvider:
+		return string(SourceEnv)
+	case *FlagProvider:
+		return string(SourceFlag)
+	default:
+		return fmt.Sprintf("%s:%T", SourceProvider, provider)
+	}
+}
+
+// config prints the resolved configuration in the format selected by the
+// output flag
+func config(ctx *Context) error {
+	resolved := ctx.Config()
+
+	if name, _ := parseOutput(ctx.output()); name != OutputTable {
+		return ctx.Render(resolved)
+	}
+
+	if err := ctx.Render(resolved.Values); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(ctx.Writer)
+	fmt.Fprintln(ctx.Writer, ctx.Translate("config.precedence"), strings.Join(resolved.Precedence, " < "))
+	return nil
+}
+
+// printConfig returns true if the --print-config flag is set
+func (ctx *Context) printConfig() bool {
+	if flag := ctx.lookup("print-config"); flag != nil {
+		printing, _ := flag.Value().(bool)
+		return printing
+	}
+
+	return false
+}
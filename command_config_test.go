@@ -0,0 +1,132 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/phogolabs/cli"
+	"github.com/phogolabs/cli/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var (
+		app      *cli.App
+		buffer   *bytes.Buffer
+		env      map[string]string
+		executed bool
+	)
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		executed = false
+
+		env = map[string]string{
+			"APP_LISTEN": ":9090",
+			"APP_TOKEN":  "secret",
+		}
+
+		app = &cli.App{
+			Name:        "app",
+			HideVersion: true,
+			Writer:      buffer,
+			LookupEnv: func(key string) (string, bool) {
+				value, ok := env[key]
+				return value, ok
+			},
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "listen", EnvVar: "APP_LISTEN", Value: ":8080"},
+				&cli.StringFlag{Name: "log-level", Value: "info"},
+				&cli.SecretFlag{Name: "token", EnvVar: "APP_TOKEN"},
+				cli.NewOutputFlag(),
+				cli.NewPrintConfigFlag(),
+			},
+			Commands: []*cli.Command{
+				&cli.Command{
+					Name: "deploy",
+					Flags: []cli.Flag{
+						&cli.IntFlag{Name: "replicas", Value: 1},
+					},
+					Action: func(ctx *cli.Context) error {
+						executed = true
+						return nil
+					},
+				},
+				cli.NewConfigCommand(),
+			},
+		}
+	})
+
+	It("prints the values and their sources", func() {
+		args := []string{"app", "--print-config", "--log-level", "debug", "deploy", "--replicas", "3"}
+
+		Expect(app.RunContext(context.Background(), args)).To(Succeed())
+		Expect(executed).To(BeFalse())
+		Expect(buffer.String()).To(Equal(
+			"COMMAND     FLAG       VALUE   SOURCE\n" +
+				"app         listen     :9090   env:APP_LISTEN\n" +
+				"app         log-level  debug   flag:log-level\n" +
+				"app         token      ******  env:APP_TOKEN\n" +
+				"app         output     table   default\n" +
+				"app deploy  replicas   3       flag:replicas\n" +
+				"\n" +
+				"PRECEDENCE: default < path < env < flag < document\n",
+		))
+	})
+
+	It("lists the custom providers in the precedence", func() {
+		app.Providers = []cli.Provider{&fake.Provider{}}
+
+		Expect(app.RunContext(context.Background(), []string{"app", "--print-config"})).To(Succeed())
+		Expect(buffer.String()).To(ContainSubstring("PRECEDENCE: default < path < env < flag < document < provider:*fake.Provider\n"))
+	})
+
+	Context("when the output is yaml", func() {
+		It("prints the configuration as yaml", func() {
+			args := []string{"app", "--print-config", "-o", "yaml"}
+
+			Expect(app.RunContext(context.Background(), args)).To(Succeed())
+			Expect(buffer.String()).To(HavePrefix("precedence:\n- default\n- path\n- env\n- flag\n- document\nvalues:\n"))
+			Expect(buffer.String()).To(ContainSubstring("- command: app\n  flag: token\n  value: '******'\n  source: env:APP_TOKEN\n"))
+			Expect(buffer.String()).NotTo(ContainSubstring("secret"))
+		})
+	})
+
+	Context("when the output is json", func() {
+		It("prints the configuration as json", func() {
+			args := []string{"app", "--print-config", "-o", "json"}
+
+			Expect(app.RunContext(context.Background(), args)).To(Succeed())
+			Expect(buffer.String()).To(ContainSubstring("\"flag\": \"listen\",\n      \"value\": \":9090\",\n      \"source\": \"env:APP_LISTEN\""))
+		})
+	})
+
+	Context("when the config show command is executed", func() {
+		It("prints the configuration of the parent command", func() {
+			Expect(app.RunContext(context.Background(), []string{"app", "config", "show"})).To(Succeed())
+			Expect(buffer.String()).To(HavePrefix("COMMAND  FLAG       VALUE   SOURCE\napp      listen     :9090   env:APP_LISTEN\n"))
+			Expect(buffer.String()).NotTo(ContainSubstring("replicas"))
+		})
+	})
+
+	Context("when the secret is not set", func() {
+		It("does not mask the empty value", func() {
+			delete(env, "APP_TOKEN")
+
+			ctx := &cli.Context{
+				Command: &cli.Command{
+					Name:     "app",
+					HelpName: "app",
+					Flags:    []cli.Flag{&cli.SecretFlag{Name: "token"}},
+				},
+			}
+
+			Expect(ctx.Config().Values).To(Equal([]*cli.ConfigValue{
+				{Command: "app", Flag: "token", Value: "", Source: "default"},
+			}))
+			Expect(ctx.Config().Precedence).To(Equal([]string{"default"}))
+		})
+	})
+})
@@ -3,9 +3,11 @@ package cli_test
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"sort"
 
 	"github.com/phogolabs/cli"
+	"github.com/phogolabs/cli/fake"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -59,7 +61,7 @@ var _ = Describe("Command", func() {
 			buffer = &bytes.Buffer{}
 
 			cmd.Action = func(ctx *cli.Context) error {
-				Expect(ctx.Command).To(Equal(cmd))
+				Expect(ctx.Command.Name).To(Equal(cmd.Name))
 				return nil
 			}
 
@@ -73,12 +75,25 @@ var _ = Describe("Command", func() {
 			Expect(cmd.RunWithContext(ctx)).To(Succeed())
 		})
 
+		It("does not change the command", func() {
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+
+			Expect(cmd.Flags).To(HaveLen(2))
+			Expect(cmd.Commands).To(HaveLen(3))
+			Expect(cmd.Providers).To(BeEmpty())
+			Expect(cmd.Metadata).To(BeNil())
+			Expect(cmd.Commands[0].HelpName).To(BeEmpty())
+
+			ctx.Command = cmd
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+
 		Context("when the parser fails", func() {
 			BeforeEach(func() {
 				ctx.Args = []string{"-unknonw-flag"}
 
 				cmd.OnUsageError = func(ctx *cli.Context, err error) error {
-					Expect(ctx.Command).To(Equal(cmd))
+					Expect(ctx.Command.Name).To(Equal(cmd.Name))
 					Expect(err).To(MatchError("flag provided but not defined: -unknonw-flag"))
 					return err
 				}
@@ -91,7 +106,7 @@ var _ = Describe("Command", func() {
 			Context("when we hide the error", func() {
 				BeforeEach(func() {
 					cmd.OnUsageError = func(ctx *cli.Context, err error) error {
-						Expect(ctx.Command).To(Equal(cmd))
+						Expect(ctx.Command.Name).To(Equal(cmd.Name))
 						Expect(err).To(MatchError("flag provided but not defined: -unknonw-flag"))
 						return nil
 					}
@@ -118,10 +133,305 @@ var _ = Describe("Command", func() {
 			})
 		})
 
+		Context("when a custom provider sets the value", func() {
+			BeforeEach(func() {
+				provider := &fake.Provider{}
+				provider.ProvideStub = func(ctx *cli.Context) error {
+					return ctx.Command.Flags[0].Set("/tmp")
+				}
+
+				cmd.Providers = []cli.Provider{provider}
+
+				cmd.Action = func(ctx *cli.Context) error {
+					Expect(ctx.IsSet("dir")).To(BeTrue())
+					Expect(ctx.Source("dir")).To(Equal(&cli.Source{Kind: cli.SourceProvider, Name: "*fake.Provider"}))
+					Expect(ctx.IsSet("path")).To(BeFalse())
+					return nil
+				}
+			})
+
+			It("tracks the source of the value", func() {
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			})
+
+			Context("when the value is provided by the command line", func() {
+				BeforeEach(func() {
+					ctx.Args = []string{"-d", "/var"}
+
+					cmd.Action = func(ctx *cli.Context) error {
+						Expect(ctx.String("dir")).To(Equal("/tmp"))
+						Expect(ctx.Source("d").Kind).To(Equal(cli.SourceProvider))
+						return nil
+					}
+				})
+
+				It("tracks the last source of the value", func() {
+					Expect(cmd.RunWithContext(ctx)).To(Succeed())
+				})
+			})
+		})
+
+		Context("when the precedence is set", func() {
+			BeforeEach(func() {
+				cmd.Flags[0] = &cli.StringFlag{
+					Name:   "dir, d",
+					EnvVar: "APP_DIR",
+				}
+
+				ctx.Args = []string{"-d", "/var"}
+				ctx.LookupEnv = func(key string) (string, bool) {
+					if key == "APP_DIR" {
+						return "/tmp", true
+					}
+
+					return "", false
+				}
+			})
+
+			It("applies the providers in the given order", func() {
+				cmd.Precedence = []cli.Provider{
+					&cli.FlagProvider{},
+					&cli.EnvProvider{},
+				}
+
+				cmd.Action = func(ctx *cli.Context) error {
+					Expect(ctx.String("dir")).To(Equal("/tmp"))
+					Expect(ctx.Source("dir")).To(Equal(&cli.Source{Kind: cli.SourceEnv, Name: "APP_DIR"}))
+					return nil
+				}
+
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			})
+
+			It("applies the custom providers between the built-in providers", func() {
+				provider := &fake.Provider{}
+				provider.ProvideStub = func(ctx *cli.Context) error {
+					Expect(ctx.Command.Flags[0].Get()).To(Equal("/tmp"))
+					return ctx.Command.Flags[0].Set("/opt")
+				}
+
+				cmd.Precedence = []cli.Provider{
+					&cli.EnvProvider{},
+					provider,
+					&cli.FlagProvider{},
+				}
+
+				cmd.Action = func(ctx *cli.Context) error {
+					Expect(ctx.String("dir")).To(Equal("/var"))
+					return nil
+				}
+
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+				Expect(provider.ProvideCallCount()).To(Equal(1))
+			})
+
+			It("is inherited by the child commands", func() {
+				cmd.Precedence = []cli.Provider{&cli.FlagProvider{}}
+				cmd.Flags[0] = &cli.StringFlag{Name: "dir, d"}
+
+				cmd.Commands[0].Flags = []cli.Flag{
+					&cli.StringFlag{Name: "name", EnvVar: "APP_DIR"},
+				}
+
+				cmd.Commands[0].Action = func(ctx *cli.Context) error {
+					Expect(ctx.String("name")).To(BeEmpty())
+					Expect(ctx.GlobalString("dir")).To(Equal("/var"))
+					return nil
+				}
+
+				ctx.Args = []string{"-d", "/var", "child1"}
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			})
+
+			Context("when the precedence is empty", func() {
+				It("turns the built-in providers off", func() {
+					cmd.Precedence = []cli.Provider{}
+					ctx.Args = []string{}
+
+					cmd.Action = func(ctx *cli.Context) error {
+						Expect(ctx.IsSet("dir")).To(BeFalse())
+						return nil
+					}
+
+					Expect(cmd.RunWithContext(ctx)).To(Succeed())
+				})
+			})
+		})
+
+		Context("when a deprecated flag is used", func() {
+			var errBuffer *bytes.Buffer
+
+			BeforeEach(func() {
+				errBuffer = &bytes.Buffer{}
+				ctx.ErrWriter = errBuffer
+
+				cmd.Flags = append(cmd.Flags,
+					&cli.StringFlag{
+						Name:       "log",
+						Deprecated: "use --verbosity instead",
+					},
+					&cli.StringFlag{
+						Name:   "listen, ~listen-addr",
+						EnvVar: "APP_LISTEN, ~APP_LISTEN_ADDR",
+					},
+				)
+			})
+
+			AfterEach(func() {
+				Expect(os.Unsetenv("APP_LISTEN_ADDR")).To(Succeed())
+			})
+
+			It("writes a warning", func() {
+				ctx.Args = []string{"-log", "debug"}
+
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+				Expect(errBuffer.String()).To(Equal("warning: flag 'log' (flag:log) is deprecated: use --verbosity instead\n"))
+			})
+
+			Context("when the flag is set by a deprecated name", func() {
+				It("writes a warning", func() {
+					ctx.Args = []string{"--listen-addr", ":8080"}
+
+					Expect(cmd.RunWithContext(ctx)).To(Succeed())
+					Expect(errBuffer.String()).To(Equal("warning: flag '--listen-addr' is deprecated: use '--listen' instead\n"))
+				})
+			})
+
+			Context("when the flag is set by a deprecated env variable", func() {
+				It("writes a warning", func() {
+					Expect(os.Setenv("APP_LISTEN_ADDR", ":8080")).To(Succeed())
+
+					Expect(cmd.RunWithContext(ctx)).To(Succeed())
+					Expect(errBuffer.String()).To(Equal("warning: environment variable 'APP_LISTEN_ADDR' is deprecated: use 'APP_LISTEN' instead\n"))
+				})
+			})
+
+			Context("when the flag is not used", func() {
+				It("does not write a warning", func() {
+					Expect(cmd.RunWithContext(ctx)).To(Succeed())
+					Expect(errBuffer.String()).To(BeEmpty())
+				})
+			})
+
+			Context("when the help is shown", func() {
+				It("hides the deprecated flags", func() {
+					ctx.Args = []string{"-h"}
+
+					Expect(cmd.RunWithContext(ctx)).To(Succeed())
+					Expect(buffer.String()).To(ContainSubstring("--help-all"))
+					Expect(buffer.String()).NotTo(ContainSubstring("--log"))
+					Expect(buffer.String()).NotTo(ContainSubstring("DEPRECATED"))
+				})
+
+				Context("when --help-all is passed", func() {
+					It("shows the deprecated flags", func() {
+						ctx.Args = []string{"--help-all"}
+
+						Expect(cmd.RunWithContext(ctx)).To(Succeed())
+						Expect(buffer.String()).To(ContainSubstring("DEPRECATED"))
+						Expect(buffer.String()).To(ContainSubstring("(deprecated: use --verbosity instead)"))
+						Expect(buffer.String()).To(ContainSubstring("(deprecated: use --listen instead)"))
+					})
+				})
+			})
+		})
+
+		Context("when a deprecated command is executed", func() {
+			var errBuffer *bytes.Buffer
+
+			BeforeEach(func() {
+				errBuffer = &bytes.Buffer{}
+				ctx.ErrWriter = errBuffer
+
+				cmd.Commands[0].Aliases = []string{"~child-one"}
+				cmd.Commands[0].Action = func(ctx *cli.Context) error {
+					return nil
+				}
+
+				cmd.Commands[2].Deprecated = "use child1 instead"
+				cmd.Commands[2].Action = func(ctx *cli.Context) error {
+					return nil
+				}
+			})
+
+			It("writes a warning", func() {
+				ctx.Args = []string{"child3"}
+
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+				Expect(errBuffer.String()).To(Equal("warning: command 'child3' is deprecated: use child1 instead\n"))
+			})
+
+			Context("when the command is executed by a deprecated alias", func() {
+				It("writes a warning", func() {
+					ctx.Args = []string{"child-one"}
+
+					Expect(cmd.RunWithContext(ctx)).To(Succeed())
+					Expect(errBuffer.String()).To(Equal("warning: command 'child-one' is deprecated: use 'child1' instead\n"))
+				})
+			})
+
+			It("hides the deprecated command and aliases", func() {
+				Expect(cmd.VisibleCommands()).To(HaveLen(1))
+				Expect(cmd.Commands[0].Names()).To(Equal([]string{"child1"}))
+				Expect(cmd.DeprecatedItems()).To(Equal([]string{
+					"child-one\t(deprecated: use child1 instead)",
+					"child3\t(deprecated: use child1 instead)",
+				}))
+			})
+		})
+
+		Context("when prefix matching is allowed", func() {
+			BeforeEach(func() {
+				cmd.AllowPrefixMatching = true
+
+				cmd.Commands[0].Action = func(child *cli.Context) error {
+					Expect(child.Command.Name).To(Equal(cmd.Commands[0].Name))
+					return nil
+				}
+
+				cmd.Commands[2].Name = "other"
+			})
+
+			It("runs the command successfully", func() {
+				ctx.Args = []string{"ch"}
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			})
+
+			Context("when the prefix is ambiguous", func() {
+				BeforeEach(func() {
+					cmd.Commands[2].Name = "child3"
+				})
+
+				It("returns an error", func() {
+					ctx.Args = []string{"ch"}
+
+					err := cmd.RunWithContext(ctx)
+					Expect(err).To(MatchError("command 'ch' is ambiguous, candidates are: child1, child3"))
+
+					errx, ok := err.(cli.ExitCoder)
+					Expect(ok).To(BeTrue())
+					Expect(errx.Code()).To(Equal(cli.ExitCodeAmbiguousCommand))
+				})
+			})
+
+			Context("when the prefix matching is not allowed", func() {
+				BeforeEach(func() {
+					cmd.AllowPrefixMatching = false
+				})
+
+				It("shows the help", func() {
+					ctx.Args = []string{"ch"}
+
+					Expect(cmd.RunWithContext(ctx)).To(Succeed())
+					Expect(buffer.String()).To(Equal("No help topic for 'ch'\n"))
+				})
+			})
+		})
+
 		Context("when a subcommand is executed", func() {
 			BeforeEach(func() {
 				ctx.Command.Commands[0].Action = func(child *cli.Context) error {
-					Expect(child.Command).To(Equal(ctx.Command.Commands[0]))
+					Expect(child.Command.Name).To(Equal("child1"))
 					return nil
 				}
 
@@ -225,6 +535,71 @@ var _ = Describe("Command", func() {
 				Expect(cmd.RunWithContext(ctx)).To(MatchError("oh no!"))
 			})
 		})
+
+		Context("when middleware is used", func() {
+			var calls []string
+
+			trace := func(name string) cli.MiddlewareFunc {
+				return func(next cli.ActionFunc) cli.ActionFunc {
+					return func(ctx *cli.Context) error {
+						calls = append(calls, name+":"+ctx.Command.Name)
+						err := next(ctx)
+						calls = append(calls, "/"+name)
+						return err
+					}
+				}
+			}
+
+			BeforeEach(func() {
+				calls = []string{}
+
+				cmd.Action = func(ctx *cli.Context) error {
+					calls = append(calls, "action")
+					return nil
+				}
+
+				cmd.Use(trace("a"), trace("b"))
+			})
+
+			It("wraps the action in order", func() {
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+				Expect(calls).To(Equal([]string{"a:run", "b:run", "action", "/b", "/a"}))
+			})
+
+			It("is inherited by the child commands", func() {
+				cmd.Commands[0].Use(trace("c"))
+				cmd.Commands[0].Action = func(ctx *cli.Context) error {
+					calls = append(calls, "child")
+					return nil
+				}
+
+				ctx.Args = []string{"child1"}
+
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+				Expect(calls).To(Equal([]string{"a:child1", "b:child1", "c:child1", "child", "/c", "/b", "/a"}))
+				Expect(cmd.Commands[0].Middleware).To(HaveLen(1))
+			})
+
+			It("does not wrap the help", func() {
+				ctx.Args = []string{"help"}
+
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+				Expect(calls).To(BeEmpty())
+			})
+
+			Context("when the middleware returns an error", func() {
+				It("does not run the action", func() {
+					cmd.Use(func(next cli.ActionFunc) cli.ActionFunc {
+						return func(ctx *cli.Context) error {
+							return fmt.Errorf("access denied")
+						}
+					})
+
+					Expect(cmd.RunWithContext(ctx)).To(MatchError("access denied"))
+					Expect(calls).To(Equal([]string{"a:run", "b:run", "/b", "/a"}))
+				})
+			})
+		})
 	})
 
 	Describe("Names", func() {
@@ -246,7 +621,7 @@ var _ = Describe("Command", func() {
 
 			Expect(cmd.RunWithContext(ctx)).To(Succeed())
 
-			flags := cmd.VisibleFlags()
+			flags := ctx.Command.VisibleFlags()
 			Expect(flags).To(HaveLen(2))
 		})
 	})
@@ -1,12 +1,16 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net"
 	"net/url"
 	"os"
+	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,12 +24,42 @@ type Context struct {
 	Command *Command
 	// Parent Context
 	Parent *Context
+	// Reader reads the input
+	Reader io.Reader
 	// Writer writer to write output to
 	Writer io.Writer
 	// ErrWriter writes error output
 	ErrWriter io.Writer
+	// Terminal prompts for missing flag values
+	Terminal Terminal
+	// LookupEnv retrieves the environment variables. Defaults to
+	// os.LookupEnv
+	LookupEnv func(key string) (string, bool)
+	// Environ lists the environment variables in the form key=value.
+	// Defaults to os.Environ
+	Environ func() []string
+	// Locale selects the message catalog. It defaults to the locale of the
+	// environment
+	Locale string
 	// Metadata store
 	Metadata map[string]interface{}
+	// sources of the flag values
+	sources map[Flag]*Source
+	// base is cancelled when the application receives a signal
+	base   context.Context
+	cancel context.CancelFunc
+	// current is the context of the running command
+	current *atomic.Pointer[Context]
+	// services are shared by the contexts of the command tree
+	services *registry
+	// resolving are the services being created when the context is passed
+	// to a provider, and origin is the context it is a copy of
+	resolving []reflect.Type
+	origin    *Context
+	// values guards the values of the flags that are reloaded
+	values *sync.RWMutex
+	// router delivers the signals received by the application
+	router *router
 }
 
 // EnvVars returns the environment variables.
@@ -36,10 +70,17 @@ func (ctx *Context) EnvVars() map[string]string {
 		variables = parent.EnvVars()
 	}
 
+	defer ctx.rlock()()
+
 	for _, flag := range ctx.Command.Flags {
 		accessor := NewFlagAccessor(flag)
 
-		for _, name := range split(accessor.EnvVar()) {
+		// secrets are never exposed in plain text
+		if accessor.IsSecretFlag() {
+			continue
+		}
+
+		for _, name := range visible(accessor.EnvVar()) {
 			// If the value is empty, we don't want to set the environment variable
 			if name != "" {
 				if value := fmt.Sprintf("%v", accessor.Value()); value != "" {
@@ -52,11 +93,69 @@ func (ctx *Context) EnvVars() map[string]string {
 	return variables
 }
 
+// Done returns a channel that is closed when the application receives one of
+// the handled signals. It returns nil if the context cannot be cancelled.
+func (ctx *Context) Done() <-chan struct{} {
+	for current := ctx; current != nil; current = current.Parent {
+		if current.base != nil {
+			return current.base.Done()
+		}
+	}
+
+	return nil
+}
+
+// IsSet returns true if the value of a local flag has been provided by any
+// source other than its default
+func (ctx *Context) IsSet(name string) bool {
+	if flag := ctx.find(name); flag != nil {
+		return flag.IsSet
+	}
+
+	return false
+}
+
+// GlobalIsSet returns true if the value of a global flag has been provided by
+// any source other than its default
+func (ctx *Context) GlobalIsSet(name string) bool {
+	if flag := ctx.findAll(name); flag != nil {
+		return flag.IsSet
+	}
+
+	return false
+}
+
+// Source looks up the origin of a local flag's value, returns nil if not found
+func (ctx *Context) Source(name string) *Source {
+	if flag := ctx.find(name); flag != nil {
+		return ctx.source(flag.Flag)
+	}
+
+	return nil
+}
+
+// GlobalSource looks up the origin of a global flag's value, returns nil if not found
+func (ctx *Context) GlobalSource(name string) *Source {
+	if ctx.Parent != nil {
+		ctx = ctx.Parent
+	}
+
+	for ctx != nil {
+		if flag := ctx.find(name); flag != nil {
+			return ctx.source(flag.Flag)
+		}
+
+		ctx = ctx.Parent
+	}
+
+	return nil
+}
+
 // Bool looks up the value of a local BoolFlag, returns
 // false if not found
 func (ctx *Context) Bool(name string) bool {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(bool); ok {
+		if value, ok := ctx.value(flag).(bool); ok {
 			return value
 		}
 	}
@@ -68,7 +167,7 @@ func (ctx *Context) Bool(name string) bool {
 // false if not found
 func (ctx *Context) GlobalBool(name string) bool {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(bool); ok {
+		if value, ok := ctx.value(flag).(bool); ok {
 			return value
 		}
 	}
@@ -79,7 +178,7 @@ func (ctx *Context) GlobalBool(name string) bool {
 // String looks up the value of a local StringFlag, returns "" if not found
 func (ctx *Context) String(name string) string {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(string); ok {
+		if value, ok := ctx.value(flag).(string); ok {
 			return value
 		}
 	}
@@ -90,7 +189,7 @@ func (ctx *Context) String(name string) string {
 // GlobalString looks up the value of a global StringFlag, returns "" if not found
 func (ctx *Context) GlobalString(name string) string {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(string); ok {
+		if value, ok := ctx.value(flag).(string); ok {
 			return value
 		}
 	}
@@ -102,7 +201,7 @@ func (ctx *Context) GlobalString(name string) string {
 // nil if not found
 func (ctx *Context) StringSlice(name string) []string {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().([]string); ok {
+		if value, ok := ctx.value(flag).([]string); ok {
 			return value
 		}
 	}
@@ -114,7 +213,7 @@ func (ctx *Context) StringSlice(name string) []string {
 // nil if not found
 func (ctx *Context) GlobalStringSlice(name string) []string {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().([]string); ok {
+		if value, ok := ctx.value(flag).([]string); ok {
 			return value
 		}
 	}
@@ -125,7 +224,7 @@ func (ctx *Context) GlobalStringSlice(name string) []string {
 // URL looks up the value of a local URLFlag, returns nil if not found
 func (ctx *Context) URL(name string) *url.URL {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(*url.URL); ok {
+		if value, ok := ctx.value(flag).(*url.URL); ok {
 			return value
 		}
 	}
@@ -136,7 +235,7 @@ func (ctx *Context) URL(name string) *url.URL {
 // GlobalURL looks up the value of a global URLFlag, returns nil if not found
 func (ctx *Context) GlobalURL(name string) *url.URL {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(*url.URL); ok {
+		if value, ok := ctx.value(flag).(*url.URL); ok {
 			return value
 		}
 	}
@@ -147,7 +246,7 @@ func (ctx *Context) GlobalURL(name string) *url.URL {
 // Time looks up the value of a local TimeFlag, returns 0 if not found
 func (ctx *Context) Time(name string) time.Time {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(time.Time); ok {
+		if value, ok := ctx.value(flag).(time.Time); ok {
 			return value
 		}
 	}
@@ -158,7 +257,7 @@ func (ctx *Context) Time(name string) time.Time {
 // GlobalTime looks up the value of a global TimeFlag, returns 0 if not found
 func (ctx *Context) GlobalTime(name string) time.Time {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(time.Time); ok {
+		if value, ok := ctx.value(flag).(time.Time); ok {
 			return value
 		}
 	}
@@ -169,7 +268,7 @@ func (ctx *Context) GlobalTime(name string) time.Time {
 // Duration looks up the value of a local DurationFlag, returns 0 if not found
 func (ctx *Context) Duration(name string) time.Duration {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(time.Duration); ok {
+		if value, ok := ctx.value(flag).(time.Duration); ok {
 			return value
 		}
 	}
@@ -180,7 +279,7 @@ func (ctx *Context) Duration(name string) time.Duration {
 // GlobalDuration looks up the value of a global DurationFlag, returns 0 if not found
 func (ctx *Context) GlobalDuration(name string) time.Duration {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(time.Duration); ok {
+		if value, ok := ctx.value(flag).(time.Duration); ok {
 			return value
 		}
 	}
@@ -191,7 +290,7 @@ func (ctx *Context) GlobalDuration(name string) time.Duration {
 // Float32 looks up the value of a local Float32Flag, returns 0 if not found
 func (ctx *Context) Float32(name string) float32 {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(float32); ok {
+		if value, ok := ctx.value(flag).(float32); ok {
 			return value
 		}
 	}
@@ -202,7 +301,7 @@ func (ctx *Context) Float32(name string) float32 {
 // GlobalFloat32 looks up the value of a global Float64Flag, returns 0 if not found
 func (ctx *Context) GlobalFloat32(name string) float32 {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(float32); ok {
+		if value, ok := ctx.value(flag).(float32); ok {
 			return value
 		}
 	}
@@ -213,7 +312,7 @@ func (ctx *Context) GlobalFloat32(name string) float32 {
 // Float64 looks up the value of a local Float64Flag, returns 0 if not found
 func (ctx *Context) Float64(name string) float64 {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(float64); ok {
+		if value, ok := ctx.value(flag).(float64); ok {
 			return value
 		}
 	}
@@ -224,7 +323,7 @@ func (ctx *Context) Float64(name string) float64 {
 // GlobalFloat64 looks up the value of a global Float64Flag, returns 0 if not found
 func (ctx *Context) GlobalFloat64(name string) float64 {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(float64); ok {
+		if value, ok := ctx.value(flag).(float64); ok {
 			return value
 		}
 	}
@@ -235,7 +334,7 @@ func (ctx *Context) GlobalFloat64(name string) float64 {
 // Int looks up the value of a local IntFlag, returns 0 if not found
 func (ctx *Context) Int(name string) int {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(int); ok {
+		if value, ok := ctx.value(flag).(int); ok {
 			return value
 		}
 	}
@@ -246,7 +345,7 @@ func (ctx *Context) Int(name string) int {
 // GlobalInt looks up the value of a global IntFlag, returns 0 if not found
 func (ctx *Context) GlobalInt(name string) int {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(int); ok {
+		if value, ok := ctx.value(flag).(int); ok {
 			return value
 		}
 	}
@@ -257,7 +356,7 @@ func (ctx *Context) GlobalInt(name string) int {
 // Int64 looks up the value of a local Int64Flag, returns 0 if not found
 func (ctx *Context) Int64(name string) int64 {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(int64); ok {
+		if value, ok := ctx.value(flag).(int64); ok {
 			return value
 		}
 	}
@@ -268,7 +367,7 @@ func (ctx *Context) Int64(name string) int64 {
 // GlobalInt64 looks up the value of a global Int64Flag, returns 0 if not found
 func (ctx *Context) GlobalInt64(name string) int64 {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(int64); ok {
+		if value, ok := ctx.value(flag).(int64); ok {
 			return value
 		}
 	}
@@ -279,7 +378,7 @@ func (ctx *Context) GlobalInt64(name string) int64 {
 // UInt looks up the value of a local UIntFlag, returns 0 if not found
 func (ctx *Context) UInt(name string) uint {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(uint); ok {
+		if value, ok := ctx.value(flag).(uint); ok {
 			return value
 		}
 	}
@@ -290,7 +389,7 @@ func (ctx *Context) UInt(name string) uint {
 // GlobalUInt looks up the value of a global UIntFlag, returns 0 if not found
 func (ctx *Context) GlobalUInt(name string) uint {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(uint); ok {
+		if value, ok := ctx.value(flag).(uint); ok {
 			return value
 		}
 	}
@@ -301,7 +400,7 @@ func (ctx *Context) GlobalUInt(name string) uint {
 // UInt64 looks up the value of a local UInt64Flag, returns 0 if not found
 func (ctx *Context) UInt64(name string) uint64 {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(uint64); ok {
+		if value, ok := ctx.value(flag).(uint64); ok {
 			return value
 		}
 	}
@@ -312,7 +411,7 @@ func (ctx *Context) UInt64(name string) uint64 {
 // GlobalUInt64 looks up the value of a global UInt64Flag, returns 0 if not found
 func (ctx *Context) GlobalUInt64(name string) uint64 {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(uint64); ok {
+		if value, ok := ctx.value(flag).(uint64); ok {
 			return value
 		}
 	}
@@ -323,7 +422,7 @@ func (ctx *Context) GlobalUInt64(name string) uint64 {
 // IP looks up the value of a local IPFlag, returns nil if not found
 func (ctx *Context) IP(name string) net.IP {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(net.IP); ok {
+		if value, ok := ctx.value(flag).(net.IP); ok {
 			return value
 		}
 	}
@@ -334,7 +433,7 @@ func (ctx *Context) IP(name string) net.IP {
 // GlobalIP looks up the value of a global IPFlag, returns nil if not found
 func (ctx *Context) GlobalIP(name string) net.IP {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(net.IP); ok {
+		if value, ok := ctx.value(flag).(net.IP); ok {
 			return value
 		}
 	}
@@ -345,7 +444,7 @@ func (ctx *Context) GlobalIP(name string) net.IP {
 // HardwareAddr looks up the value of a local HardwareddrFlag, returns nil if not found
 func (ctx *Context) HardwareAddr(name string) net.HardwareAddr {
 	if flag := ctx.find(name); flag != nil {
-		if value, ok := flag.Value().(net.HardwareAddr); ok {
+		if value, ok := ctx.value(flag).(net.HardwareAddr); ok {
 			return value
 		}
 	}
@@ -356,7 +455,7 @@ func (ctx *Context) HardwareAddr(name string) net.HardwareAddr {
 // GlobalHardwareAddr looks up the value of a global HardwareAddrFlag, returns nil if not found
 func (ctx *Context) GlobalHardwareAddr(name string) net.HardwareAddr {
 	if flag := ctx.findAll(name); flag != nil {
-		if value, ok := flag.Value().(net.HardwareAddr); ok {
+		if value, ok := ctx.value(flag).(net.HardwareAddr); ok {
 			return value
 		}
 	}
@@ -367,7 +466,7 @@ func (ctx *Context) GlobalHardwareAddr(name string) net.HardwareAddr {
 // Get looks up the value of a local flag, returns nil if not found
 func (ctx *Context) Get(name string) interface{} {
 	if flag := ctx.find(name); flag != nil {
-		return flag.Value()
+		return ctx.value(flag)
 	}
 
 	return nil
@@ -376,7 +475,7 @@ func (ctx *Context) Get(name string) interface{} {
 // GlobalGet looks up the value of a global flag, returns nil if not found
 func (ctx *Context) GlobalGet(name string) interface{} {
 	if flag := ctx.findAll(name); flag != nil {
-		return flag.Value()
+		return ctx.value(flag)
 	}
 
 	return nil
@@ -398,14 +497,87 @@ func (ctx *Context) findAll(name string) *FlagAccessor {
 	return nil
 }
 
+// signal sets the signal on the context of the running command and its
+// parents and cancels them
+func (ctx *Context) signal(sig os.Signal) {
+	running := ctx
+
+	if ctx.current != nil {
+		if current := ctx.current.Load(); current != nil {
+			running = current
+		}
+	}
+
+	for current := running; current != nil; current = current.Parent {
+		current.Signal = sig
+	}
+
+	ctx.cancel()
+}
+
+// getenv returns the value of the environment variable
+func (ctx *Context) getenv(key string) string {
+	value, _ := ctx.lookupEnv(key)
+	return value
+}
+
+func (ctx *Context) lookupEnv(key string) (string, bool) {
+	for current := ctx; current != nil; current = current.Parent {
+		if current.LookupEnv != nil {
+			return current.LookupEnv(key)
+		}
+	}
+
+	return os.LookupEnv(key)
+}
+
+// environ returns the environment variables in the form key=value. The
+// values are looked up with LookupEnv, so they match the values seen by the
+// flags.
+func (ctx *Context) environ() []string {
+	environ := os.Environ
+
+	for current := ctx; current != nil; current = current.Parent {
+		if current.Environ != nil {
+			environ = current.Environ
+			break
+		}
+	}
+
+	variables := []string{}
+
+	for _, item := range environ() {
+		key, _, _ := strings.Cut(item, "=")
+
+		if value, ok := ctx.lookupEnv(key); ok {
+			variables = append(variables, key+"="+value)
+		}
+	}
+
+	return variables
+}
+
+// lookup finds the flag in the context or in the closest parent that defines
+// it. It is used to resolve the built-in flags.
+func (ctx *Context) lookup(name string) *FlagAccessor {
+	for current := ctx; current != nil; current = current.Parent {
+		if flag := current.find(name); flag != nil {
+			return flag
+		}
+	}
+
+	return nil
+}
+
 func (ctx *Context) find(name string) *FlagAccessor {
+	defer ctx.rlock()()
+
 	for _, flag := range ctx.Command.Flags {
 		accessor := NewFlagAccessor(flag)
 
-		for _, key := range split(accessor.Name()) {
-			key = strings.TrimSpace(key)
-
+		for _, key := range names(accessor.Name()) {
 			if strings.EqualFold(name, key) {
+				accessor.IsSet = ctx.isSet(flag)
 				return accessor
 			}
 		}
@@ -413,3 +585,53 @@ func (ctx *Context) find(name string) *FlagAccessor {
 
 	return nil
 }
+
+// value returns the value of the flag
+func (ctx *Context) value(flag *FlagAccessor) interface{} {
+	defer ctx.rlock()()
+	return flag.Value()
+}
+
+// rlock locks the flags for reading, because they are changed when their
+// files are reloaded. The returned function unlocks them.
+func (ctx *Context) rlock() func() {
+	if ctx.values == nil {
+		return func() {}
+	}
+
+	ctx.values.RLock()
+	return ctx.values.RUnlock
+}
+
+// warn writes the warning with the message ID from the catalogs
+func (ctx *Context) warn(id string, args ...interface{}) {
+	if ctx.ErrWriter == nil {
+		return
+	}
+
+	fmt.Fprintln(ctx.ErrWriter, ctx.Translate("warning")+ctx.Translate(id, args...))
+}
+
+func (ctx *Context) source(flag Flag) *Source {
+	if source, ok := ctx.sources[flag]; ok {
+		return source
+	}
+
+	return &Source{Kind: SourceDefault}
+}
+
+func (ctx *Context) isSet(flag Flag) bool {
+	_, ok := ctx.sources[flag]
+	return ok
+}
+
+func (ctx *Context) track(flag Flag, kind SourceKind, name string) {
+	if ctx.sources == nil {
+		ctx.sources = make(map[Flag]*Source)
+	}
+
+	ctx.sources[flag] = &Source{
+		Kind: kind,
+		Name: name,
+	}
+}
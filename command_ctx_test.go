@@ -500,6 +500,67 @@ var _ = Describe("Context", func() {
 		})
 	})
 
+	Describe("IsSet", func() {
+		It("returns false for default values", func() {
+			Expect(context.IsSet("log-level")).To(BeFalse())
+			Expect(context.GlobalIsSet("log-level")).To(BeFalse())
+		})
+
+		Context("when the flag cannot be found", func() {
+			It("returns false", func() {
+				Expect(context.IsSet("unknown")).To(BeFalse())
+				Expect(context.GlobalIsSet("unknown")).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Source", func() {
+		It("returns the default source", func() {
+			Expect(context.Source("log-level")).To(Equal(&cli.Source{Kind: cli.SourceDefault}))
+			Expect(context.GlobalSource("log-level")).To(Equal(&cli.Source{Kind: cli.SourceDefault}))
+		})
+
+		It("formats the source", func() {
+			source := &cli.Source{Kind: cli.SourceEnv, Name: "APP_LOG_LEVEL"}
+			Expect(source.String()).To(Equal("env:APP_LOG_LEVEL"))
+			Expect(context.Source("l").String()).To(Equal("default"))
+		})
+
+		Context("when the flag cannot be found", func() {
+			It("returns nil", func() {
+				Expect(context.Source("unknown")).To(BeNil())
+				Expect(context.GlobalSource("unknown")).To(BeNil())
+			})
+		})
+	})
+
+	Describe("EnvVars", func() {
+		BeforeEach(func() {
+			context.Command.Flags = append(context.Command.Flags,
+				&cli.StringFlag{
+					Name:   "listen-addr",
+					EnvVar: "APP_LISTEN_ADDR",
+					Value:  ":8080",
+				},
+				&cli.SecretFlag{
+					Name:   "password",
+					EnvVar: "APP_PASSWORD",
+					Value:  "swordfish",
+				},
+			)
+		})
+
+		It("returns the variables", func() {
+			Expect(context.EnvVars()).To(HaveKeyWithValue("APP_LISTEN_ADDR", ":8080"))
+		})
+
+		Context("when the flag is secret", func() {
+			It("does not return the variable", func() {
+				Expect(context.EnvVars()).NotTo(HaveKey("APP_PASSWORD"))
+			})
+		})
+	})
+
 	Describe("HardwareAddr", func() {
 		It("returns the value", func() {
 			Expect(context.HardwareAddr("mac-flag")).NotTo(BeNil())
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"strings"
 )
 
@@ -14,6 +15,18 @@ const (
 	ExitCodeNotFoundFlag = 1003
 	// ExitCodeNotFoundCommand is the exit code when a command is not found
 	ExitCodeNotFoundCommand = 1004
+	// ExitCodeAmbiguousCommand is the exit code when a command prefix matches
+	// more than one command
+	ExitCodeAmbiguousCommand = 1005
+	// ExitCodeErrorTemplate is the exit code when a custom template is not
+	// valid
+	ExitCodeErrorTemplate = 1006
+	// ExitCodeErrorSignal is the exit code when the exit is forced by a
+	// repeated signal
+	ExitCodeErrorSignal = 1007
+	// ExitCodeErrorAlias is the exit code when an user-defined alias is not
+	// valid
+	ExitCodeErrorAlias = 1008
 )
 
 // ExitCoder is the interface checked by `App` and `Command` for a custom exit
@@ -29,6 +42,9 @@ var _ ExitCoder = &ExitError{}
 type ExitError struct {
 	err  error
 	code int
+	// id and args of the message used to translate the error
+	id   string
+	args []interface{}
 }
 
 // NewExitError makes a new ExitError
@@ -49,28 +65,54 @@ func WrapError(err error) *ExitError {
 
 // NotFoundFlagError makes a new ExitError for missing flags
 func NotFoundFlagError(name string) *ExitError {
-	return &ExitError{
-		code: ExitCodeNotFoundFlag,
-		err:  fmt.Errorf("flag '%s' not found", name),
-	}
+	return newError(ExitCodeNotFoundFlag, "error.flag_missing", name)
 }
 
 // FlagError makes a new ExitError for missing command
 func FlagError(prefix, name string, err error) *ExitError {
-	return &ExitError{
-		code: ExitCodeErrorFlag,
-		err:  fmt.Errorf("%s: failed to set a flag '%v': %w", prefix, name, err),
-	}
+	return newError(ExitCodeErrorFlag, "error.flag", prefix, name, err)
 }
 
 // NotFoundCommandError makes a new ExitError for missing command
 func NotFoundCommandError(name string) *ExitError {
+	return newError(ExitCodeNotFoundCommand, "error.command", name)
+}
+
+// AmbiguousCommandError makes a new ExitError for a command prefix that
+// matches more than one command
+func AmbiguousCommandError(name string, candidates []string) *ExitError {
+	return newError(ExitCodeAmbiguousCommand, "error.ambiguous", name, strings.Join(candidates, ", "))
+}
+
+// SignalError makes a new ExitError for an exit forced by a signal
+func SignalError(signal os.Signal) *ExitError {
+	return newError(ExitCodeErrorSignal, "error.signal", signal)
+}
+
+// AliasError makes a new ExitError for an user-defined alias
+func AliasError(name string, err error) *ExitError {
+	return newError(ExitCodeErrorAlias, "error.alias", name, err)
+}
+
+// newError makes a new ExitError with a message from the catalogs
+func newError(code int, id string, args ...interface{}) *ExitError {
 	return &ExitError{
-		code: ExitCodeNotFoundCommand,
-		err:  fmt.Errorf("command '%s' not found", name),
+		code: code,
+		err:  fmt.Errorf(translate(DefaultLocale, id), args...),
+		id:   id,
+		args: args,
 	}
 }
 
+// localize returns the message of the error in the locale
+func (x *ExitError) localize(locale string) string {
+	if x.id == "" {
+		return x.Error()
+	}
+
+	return translate(locale, x.id, x.args...)
+}
+
 // WithCode creates a copy of the error with a code
 func (x ExitError) WithCode(code int) *ExitError {
 	x.code = code
@@ -92,6 +134,7 @@ func (x *ExitError) Code() int {
 // Wrap wraps an error
 func (x *ExitError) Wrap(err error) {
 	x.err = err
+	x.id = ""
 }
 
 // Unwrap returns the underlying error
@@ -147,3 +190,9 @@ func (errs ExitErrorCollector) Unwrap() error {
 		return nil
 	}
 }
+
+// TemplateError makes a new ExitError for a custom template that cannot be
+// parsed
+func TemplateError(name string, err error) *ExitError {
+	return newError(ExitCodeErrorTemplate, "error.template", name, err)
+}
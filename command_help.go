@@ -1,23 +1,63 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"strings"
 	"text/tabwriter"
+	tpl "text/template"
 
 	"github.com/phogolabs/cli/template"
 )
 
+// helpMinWidth is the minimum width of a wrapped column
+const helpMinWidth = 20
+
+const (
+	// ColorAuto styles the help if the writer is a terminal
+	ColorAuto = "auto"
+	// ColorAlways styles the help
+	ColorAlways = "always"
+	// ColorNever does not style the help
+	ColorNever = "never"
+)
+
+// NewColorFlag creates the --color flag that controls the styling of the help
+func NewColorFlag() *StringFlag {
+	return &StringFlag{
+		Name:      "color",
+		Usage:     "flag.color",
+		Value:     ColorAuto,
+		Validator: OneOf(ColorAuto, ColorAlways, ColorNever),
+	}
+}
+
+// helpContent is the data of the help templates
+type helpContent struct {
+	*Command
+	// ShowDeprecated is true if the deprecated items should be shown
+	ShowDeprecated bool
+	// Metadata shadows the command metadata in order to style the flags
+	Metadata Map
+}
+
 func help(ctx *Context) error {
 	var (
 		man  string
 		name string
 		cmd  *Command
+		all  bool
 	)
 
+	if ctx.Parent != nil {
+		all = ctx.Parent.Bool("help-all")
+	}
+
 	switch {
 	case len(ctx.Args) > 0:
 		name = ctx.Args[0]
-		cmd = ctx.Parent.Command.find(name)
+		cmd, _ = ctx.Parent.Command.match(ctx.Parent, name)
 		man = "help.cmd.tpl"
 	case ctx.Parent != nil:
 		cmd = ctx.Parent.Command
@@ -29,23 +69,64 @@ func help(ctx *Context) error {
 	}
 
 	if cmd == nil {
-		fmt.Fprintf(ctx.Writer, "No help topic for '%s'", name)
+		fmt.Fprint(ctx.Writer, ctx.Translate("help.no_topic", name))
 		fmt.Fprintln(ctx.Writer)
 		return nil
 	}
 
-	writer := tabwriter.NewWriter(ctx.Writer, 1, 8, 2, ' ', 0)
+	// the help lists the plugins as commands
+	cmd.plugins(ctx)
+
+	var (
+		buffer = &bytes.Buffer{}
+		writer = tabwriter.NewWriter(buffer, 1, 8, 2, ' ', 0)
+		color  = ctx.colored()
+		locale = ctx.locale()
+		width  = cmd.HelpWidth
+		paint  = plain
+	)
 
-	content, err := template.Open(man)
+	if width == 0 {
+		width = terminalWidth(ctx.Writer, ctx.getenv("COLUMNS"))
+	}
+
+	content, err := parse(man, cmd.HelpTemplate, color, funcs(locale, cmd.TemplateFuncs))
 	if err != nil {
 		return err
 	}
 
-	if err := content.Execute(writer, cmd); err != nil {
+	if color {
+		paint = template.Colorize
+	}
+
+	data := &helpContent{
+		Command:        cmd,
+		ShowDeprecated: all,
+		Metadata:       formatted(cmd.Metadata, paint, locale),
+	}
+
+	if err := content.Execute(writer, data); err != nil {
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
 		return err
 	}
 
-	return writer.Flush()
+	// the lines are wrapped after the columns have been aligned
+	for _, line := range strings.SplitAfter(buffer.String(), "\n") {
+		if line == "" {
+			continue
+		}
+
+		for _, text := range fold(strings.TrimSuffix(line, "\n"), width) {
+			if _, err := io.WriteString(ctx.Writer, text+"\n"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 func version(ctx *Context) error {
@@ -53,7 +134,12 @@ func version(ctx *Context) error {
 		ctx = ctx.Parent
 	}
 
-	content, err := template.Open("version.app.tpl")
+	var (
+		cmd     = ctx.Command
+		text, _ = cmd.Metadata["VersionTemplate"].(string)
+	)
+
+	content, err := parse("version.app.tpl", text, ctx.colored(), funcs(ctx.locale(), cmd.TemplateFuncs))
 	if err != nil {
 		return err
 	}
@@ -64,3 +150,197 @@ func version(ctx *Context) error {
 
 	return nil
 }
+
+// parse parses the custom template text or opens the embedded template if
+// the text is empty
+func parse(name, text string, color bool, funcs map[string]interface{}) (*tpl.Template, error) {
+	if text == "" {
+		return template.Open(name, color, funcs)
+	}
+
+	return template.Parse(name, text, color, funcs)
+}
+
+// templates parses the custom templates of the command and its children in
+// order to report the errors before the command runs
+func (cmd *Command) templates(custom map[string]interface{}) error {
+	if cmd.TemplateFuncs != nil {
+		custom = cmd.TemplateFuncs
+	}
+
+	texts := []string{cmd.HelpTemplate}
+
+	if text, ok := cmd.Metadata["VersionTemplate"].(string); ok {
+		texts = append(texts, text)
+	}
+
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+
+		if _, err := template.Parse(cmd.Name, text, false, funcs(DefaultLocale, custom)); err != nil {
+			return TemplateError(cmd.Name, err)
+		}
+	}
+
+	for _, child := range cmd.Commands {
+		if err := child.templates(custom); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// funcs returns the custom template functions along with the t function that
+// translates a message to the locale
+func funcs(locale string, custom map[string]interface{}) map[string]interface{} {
+	kv := map[string]interface{}{
+		"t": func(id string, args ...interface{}) string {
+			return translate(locale, id, args...)
+		},
+	}
+
+	for key, fn := range custom {
+		kv[key] = fn
+	}
+
+	return kv
+}
+
+// formatted returns a copy of the metadata with the visible flags formatted
+// in the locale
+func formatted(metadata Map, paint painter, locale string) Map {
+	result := Map{}
+
+	for key, value := range metadata {
+		result[key] = value
+	}
+
+	if flags, ok := metadata["VisibleFlags"].([]Flag); ok {
+		items := make([]string, len(flags))
+
+		for index, flag := range flags {
+			items[index] = format(flag, paint, func(id string) string {
+				return translate(locale, id)
+			})
+		}
+
+		result["VisibleFlags"] = items
+	}
+
+	return result
+}
+
+// colored returns true if the output should be styled. The --color flag takes
+// precedence over the NO_COLOR and FORCE_COLOR environment variables. By
+// default the output is styled if the writer is a terminal.
+func (ctx *Context) colored() bool {
+	mode := ColorAuto
+
+	if flag := ctx.lookup("color"); flag != nil {
+		if text, ok := flag.Value().(string); ok && text != "" {
+			mode = text
+		}
+	}
+
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if ctx.getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	switch force := ctx.getenv("FORCE_COLOR"); force {
+	case "", "0", "false":
+	default:
+		return true
+	}
+
+	return isTerminal(ctx.Writer)
+}
+
+// fold wraps the line to the width. The continuation lines are indented to
+// the second column of the line if there is one or to the line indentation.
+// Words are never split.
+func fold(line string, width int) []string {
+	if width <= 0 || visibleLen(line) <= width {
+		return []string{line}
+	}
+
+	var (
+		text   = strings.TrimLeft(line, " ")
+		indent = len(line) - len(text)
+		head   = line[:indent]
+	)
+
+	// the columns are separated by at least two spaces
+	if index := strings.Index(text, "  "); index > 0 {
+		column := index + len(text[index:]) - len(strings.TrimLeft(text[index:], " "))
+		head = line[:indent+column]
+		text = text[column:]
+	}
+
+	var (
+		lines  = []string{}
+		offset = visibleLen(head)
+		size   = width - offset
+		buffer = &strings.Builder{}
+		length = 0
+	)
+
+	// the text is not squeezed into a column that is too narrow
+	if size < helpMinWidth {
+		size = helpMinWidth
+	}
+
+	buffer.WriteString(head)
+
+	for _, word := range strings.Fields(text) {
+		count := visibleLen(word)
+
+		if length > 0 && length+1+count > size {
+			lines = append(lines, buffer.String())
+			buffer.Reset()
+			buffer.WriteString(strings.Repeat(" ", offset))
+			length = 0
+		}
+
+		if length > 0 {
+			buffer.WriteString(" ")
+			length++
+		}
+
+		buffer.WriteString(word)
+		length += count
+	}
+
+	return append(lines, buffer.String())
+}
+
+// visibleLen returns the number of characters without the ANSI escape
+// sequences
+func visibleLen(text string) int {
+	var (
+		count  = 0
+		escape = false
+	)
+
+	for _, char := range text {
+		switch {
+		case char == '\x1b':
+			escape = true
+		case escape:
+			escape = char != 'm'
+		default:
+			count++
+		}
+	}
+
+	return count
+}
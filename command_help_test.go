@@ -2,6 +2,9 @@ package cli_test
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"strings"
 
 	"github.com/phogolabs/cli"
 	"github.com/phogolabs/cli/fake"
@@ -55,6 +58,117 @@ var _ = Describe("ShowHelp", func() {
 		})
 	})
 
+	Context("when the command has a custom template", func() {
+		BeforeEach(func() {
+			parent.Command.HelpTemplate = "{{brand .Name}} - {{header .Usage}}\n"
+			parent.Command.TemplateFuncs = map[string]interface{}{
+				"brand": strings.ToUpper,
+			}
+		})
+
+		It("shows the help with the custom template", func() {
+			Expect(cli.NewHelpCommand().Action(parent)).To(Succeed())
+			Expect(buffer.Contents()).To(BeEquivalentTo("ROOT - root_usage\n"))
+		})
+	})
+
+	Context("when the help is wider than the width", func() {
+		BeforeEach(func() {
+			parent.Command.HelpWidth = 40
+			parent.Command.Usage = "a root command with a rather long usage"
+			parent.Command.UsageText = ""
+			parent.Command.Commands[1].Usage = "runs the action and reports the result"
+		})
+
+		It("wraps the lines", func() {
+			Expect(cli.NewHelpCommand().Action(parent)).To(Succeed())
+			Expect(buffer).To(Say("\n   root - a root command with a rather\n   long usage\n"))
+		})
+
+		It("indents the wrapped lines to the second column", func() {
+			Expect(cli.NewHelpCommand().Action(parent)).To(Succeed())
+			Expect(buffer).To(Say("\n     action   runs the action and\n              reports the result\n"))
+		})
+
+		Context("when the wrapping is turned off", func() {
+			BeforeEach(func() {
+				parent.Command.HelpWidth = -1
+			})
+
+			It("does not wrap the lines", func() {
+				Expect(cli.NewHelpCommand().Action(parent)).To(Succeed())
+				Expect(buffer).To(Say("root - a root command with a rather long usage\n"))
+			})
+		})
+	})
+
+	Context("when the color is enabled", func() {
+		var flag *cli.StringFlag
+
+		BeforeEach(func() {
+			flag = cli.NewColorFlag()
+			flag.Value = cli.ColorAlways
+
+			parent.Command.Flags = []cli.Flag{flag}
+			parent.Command.Metadata["VisibleFlags"] = parent.Command.Flags
+		})
+
+		It("styles the help", func() {
+			Expect(cli.NewHelpCommand().Action(parent)).To(Succeed())
+			Expect(buffer).To(Say(regexp.QuoteMeta("\x1b[1mNAME:\x1b[0m")))
+			Expect(buffer).To(Say(regexp.QuoteMeta("\x1b[36mhelp, h\x1b[0m")))
+			Expect(buffer).To(Say(regexp.QuoteMeta("\x1b[32m--color value\x1b[0m")))
+			Expect(buffer).To(Say(regexp.QuoteMeta("(default: \x1b[2malways\x1b[0m)")))
+		})
+
+		Context("when the color is disabled by the flag", func() {
+			BeforeEach(func() {
+				flag.Value = cli.ColorNever
+				Expect(os.Setenv("FORCE_COLOR", "1")).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.Unsetenv("FORCE_COLOR")).To(Succeed())
+			})
+
+			It("does not style the help", func() {
+				Expect(cli.NewHelpCommand().Action(parent)).To(Succeed())
+				Expect(string(buffer.Contents())).NotTo(ContainSubstring("\x1b["))
+			})
+		})
+
+		Context("when the color is forced by the environment", func() {
+			BeforeEach(func() {
+				flag.Value = cli.ColorAuto
+				Expect(os.Setenv("FORCE_COLOR", "1")).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.Unsetenv("FORCE_COLOR")).To(Succeed())
+			})
+
+			It("styles the help", func() {
+				Expect(cli.NewHelpCommand().Action(parent)).To(Succeed())
+				Expect(buffer).To(Say(regexp.QuoteMeta("\x1b[1mNAME:\x1b[0m")))
+			})
+
+			Context("when NO_COLOR is set", func() {
+				BeforeEach(func() {
+					Expect(os.Setenv("NO_COLOR", "1")).To(Succeed())
+				})
+
+				AfterEach(func() {
+					Expect(os.Unsetenv("NO_COLOR")).To(Succeed())
+				})
+
+				It("does not style the help", func() {
+					Expect(cli.NewHelpCommand().Action(parent)).To(Succeed())
+					Expect(string(buffer.Contents())).NotTo(ContainSubstring("\x1b["))
+				})
+			})
+		})
+	})
+
 	Context("when the writer fails", func() {
 		BeforeEach(func() {
 			writer := &fake.Writer{}
@@ -135,4 +249,29 @@ var _ = Describe("ShowVersion", func() {
 		Expect(cli.NewVersionCommand().Action(ctx)).To(Succeed())
 		Expect(buffer).To(Say("app version BETA"))
 	})
+
+	Context("when the version has a custom template", func() {
+		It("shows the version with the custom template", func() {
+			buffer := NewBuffer()
+
+			ctx := &cli.Context{
+				Parent: &cli.Context{
+					Writer: buffer,
+					Command: &cli.Command{
+						Name: "app",
+						Metadata: cli.Map{
+							"Version":         "BETA",
+							"VersionTemplate": "{{.Name}} {{.Metadata.Version}} ({{codename}})\n",
+						},
+						TemplateFuncs: map[string]interface{}{
+							"codename": func() string { return "lynx" },
+						},
+					},
+				},
+			}
+
+			Expect(cli.NewVersionCommand().Action(ctx)).To(Succeed())
+			Expect(buffer).To(Say(`app BETA \(lynx\)`))
+		})
+	})
 })
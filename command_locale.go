@@ -0,0 +1,346 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultLocale is the locale used when no catalog matches
+const DefaultLocale = "en"
+
+// Catalog contains the messages of a locale keyed by message ID
+type Catalog map[string]string
+
+// Catalogs contains the message catalogs keyed by locale such as "de" or
+// "de_CH". The usages and the descriptions of the commands and the flags are
+// looked up in the catalogs as well, so applications can add their own
+// messages and use their IDs as usage.
+var Catalogs = map[string]Catalog{
+	"en": {
+		"help.name":                  "NAME:",
+		"help.usage":                 "USAGE:",
+		"help.version":               "VERSION:",
+		"help.description":           "DESCRIPTION:",
+		"help.author":                "AUTHOR:",
+		"help.authors":               "AUTHORS:",
+		"help.commands":              "COMMANDS:",
+		"help.global_options":        "GLOBAL OPTIONS:",
+		"help.options":               "OPTIONS:",
+		"help.category":              "CATEGORY:",
+		"help.deprecated":            "DEPRECATED:",
+		"help.copyright":             "COPYRIGHT:",
+		"help.no_topic":              "No help topic for '%s'",
+		"help.incorrect":             "Incorrect Usage:",
+		"version.name":               "version",
+		"command.help":               "Shows a list of commands or help for one command",
+		"command.version":            "Prints the version",
+		"command.config":             "Shows the configuration",
+		"command.config_show":        "Prints the resolved flag values and their sources",
+		"command.alias":              "Manages the aliases of the commands",
+		"command.alias_list":         "Lists the aliases",
+		"command.alias_add":          "Adds an alias of a command line",
+		"command.alias_remove":       "Removes an alias",
+		"command.shell":              "Runs the commands in an interactive shell",
+		"command.plugin":             "Runs a plugin",
+		"flag.help":                  "shows help",
+		"flag.help_all":              "shows help including the deprecated flags and commands",
+		"flag.version":               "prints the version",
+		"flag.color":                 "colorize the output: auto, always or never",
+		"flag.output":                "output format: json, yaml, xml, table or template=<go template>",
+		"flag.quiet":                 "do not show the progress",
+		"flag.no_headers":            "do not print the table headers",
+		"flag.print_config":          "prints the resolved configuration and exits",
+		"flag.default":               "default",
+		"config.precedence":          "PRECEDENCE:",
+		"warning":                    "warning: ",
+		"warning.flag_deprecated":    "flag '%s' (%s) is deprecated: %s",
+		"warning.flag_renamed":       "flag '%s' is deprecated: use '%s' instead",
+		"warning.env_renamed":        "environment variable '%s' is deprecated: use '%s' instead",
+		"warning.command_deprecated": "command '%s' is deprecated: %s",
+		"warning.command_renamed":    "command '%s' is deprecated: use '%s' instead",
+		"warning.reload":             "reloading the flag '%s' failed: %v",
+		"warning.signal":             "handling the signal '%v' failed: %v",
+		"warning.services":           "closing the services failed: %v",
+		"error.flag":                 "%s: failed to set a flag '%v': %w",
+		"error.flag_missing":         "flag '%s' not found",
+		"error.command":              "command '%s' not found",
+		"error.ambiguous":            "command '%s' is ambiguous, candidates are: %s",
+		"error.template":             "invalid template of '%s': %w",
+		"error.signal":               "forced exit on signal '%v'",
+		"error.alias":                "alias '%s': %w",
+		"error.value":                "unsupported value: %v",
+		"error.output":               "unsupported output format: %s",
+		"error.column":               "column '%s' not found",
+		"error.brace":                "missing '}' in a reference",
+		"error.interpolation":        "interpolation cycle: %s",
+		"error.quote":                "missing %c in %q",
+		"error.backslash":            "trailing backslash in %q",
+		"error.alias_loop":           "alias loop: %s",
+		"error.alias_map":            "%s: %s must be a map",
+		"error.alias_path":           "the alias file is not configured",
+		"error.alias_add":            "the alias requires a name and a command",
+		"error.alias_remove":         "the alias requires a name",
+		"error.alias_name":           "invalid name",
+		"error.alias_command":        "the name is used by a command",
+		"error.alias_missing":        "not found",
+		"error.shell":                "the shell requires a parent command",
+		"error.service":              "service %v: %w",
+		"error.service_missing":      "service %v not provided",
+		"error.service_cycle":        "service cycle: %s",
+	},
+	"de": {
+		"help.name":                  "NAME:",
+		"help.usage":                 "VERWENDUNG:",
+		"help.version":               "VERSION:",
+		"help.description":           "BESCHREIBUNG:",
+		"help.author":                "AUTOR:",
+		"help.authors":               "AUTOREN:",
+		"help.commands":              "BEFEHLE:",
+		"help.global_options":        "GLOBALE OPTIONEN:",
+		"help.options":               "OPTIONEN:",
+		"help.category":              "KATEGORIE:",
+		"help.deprecated":            "VERALTET:",
+		"help.copyright":             "COPYRIGHT:",
+		"help.no_topic":              "Kein Hilfethema für '%s'",
+		"help.incorrect":             "Falsche Verwendung:",
+		"version.name":               "Version",
+		"command.help":               "Zeigt eine Liste der Befehle oder die Hilfe zu einem Befehl",
+		"command.version":            "Gibt die Version aus",
+		"command.config":             "Zeigt die Konfiguration",
+		"command.config_show":        "Gibt die aufgelösten Optionswerte und ihre Quellen aus",
+		"command.alias":              "Verwaltet die Aliase der Befehle",
+		"command.alias_list":         "Listet die Aliase auf",
+		"command.alias_add":          "Fügt einen Alias für eine Befehlszeile hinzu",
+		"command.alias_remove":       "Entfernt einen Alias",
+		"command.shell":              "Führt die Befehle in einer interaktiven Shell aus",
+		"command.plugin":             "Führt ein Plugin aus",
+		"flag.help":                  "zeigt die Hilfe",
+		"flag.help_all":              "zeigt die Hilfe einschließlich der veralteten Optionen und Befehle",
+		"flag.version":               "gibt die Version aus",
+		"flag.color":                 "färbt die Ausgabe: auto, always oder never",
+		"flag.output":                "Ausgabeformat: json, yaml, xml, table oder template=<go template>",
+		"flag.quiet":                 "zeigt keinen Fortschritt an",
+		"flag.no_headers":            "gibt die Tabellenköpfe nicht aus",
+		"flag.print_config":          "gibt die aufgelöste Konfiguration aus und beendet sich",
+		"flag.default":               "Standard",
+		"config.precedence":          "RANGFOLGE:",
+		"warning":                    "Warnung: ",
+		"warning.flag_deprecated":    "Option '%s' (%s) ist veraltet: %s",
+		"warning.flag_renamed":       "Option '%s' ist veraltet: verwenden Sie stattdessen '%s'",
+		"warning.env_renamed":        "Umgebungsvariable '%s' ist veraltet: verwenden Sie stattdessen '%s'",
+		"warning.command_deprecated": "Befehl '%s' ist veraltet: %s",
+		"warning.command_renamed":    "Befehl '%s' ist veraltet: verwenden Sie stattdessen '%s'",
+		"warning.reload":             "die Option '%s' konnte nicht neu geladen werden: %v",
+		"warning.signal":             "das Signal '%v' konnte nicht behandelt werden: %v",
+		"warning.services":           "die Dienste konnten nicht geschlossen werden: %v",
+		"error.flag":                 "%s: die Option '%v' konnte nicht gesetzt werden: %w",
+		"error.flag_missing":         "Option '%s' nicht gefunden",
+		"error.command":              "Befehl '%s' nicht gefunden",
+		"error.ambiguous":            "Befehl '%s' ist mehrdeutig, Kandidaten sind: %s",
+		"error.template":             "ungültige Vorlage von '%s': %w",
+		"error.signal":               "erzwungenes Beenden durch das Signal '%v'",
+		"error.alias":                "Alias '%s': %w",
+		"error.value":                "nicht unterstützter Wert: %v",
+		"error.output":               "nicht unterstütztes Ausgabeformat: %s",
+		"error.column":               "Spalte '%s' nicht gefunden",
+		"error.brace":                "fehlende '}' in einer Ersetzung",
+		"error.interpolation":        "zyklische Ersetzung: %s",
+		"error.quote":                "fehlendes %c in %q",
+		"error.backslash":            "abschließender Backslash in %q",
+		"error.alias_loop":           "zyklischer Alias: %s",
+		"error.alias_map":            "%s: %s muss eine Zuordnung sein",
+		"error.alias_path":           "die Alias-Datei ist nicht konfiguriert",
+		"error.alias_add":            "der Alias benötigt einen Namen und einen Befehl",
+		"error.alias_remove":         "der Alias benötigt einen Namen",
+		"error.alias_name":           "ungültiger Name",
+		"error.alias_command":        "der Name wird von einem Befehl verwendet",
+		"error.alias_missing":        "nicht gefunden",
+		"error.shell":                "die Shell benötigt einen übergeordneten Befehl",
+		"error.service":              "Dienst %v: %w",
+		"error.service_missing":      "Dienst %v ist nicht bereitgestellt",
+		"error.service_cycle":        "zyklische Abhängigkeit der Dienste: %s",
+	},
+	"ja": {
+		"help.name":                  "名前:",
+		"help.usage":                 "使い方:",
+		"help.version":               "バージョン:",
+		"help.description":           "説明:",
+		"help.author":                "作者:",
+		"help.authors":               "作者:",
+		"help.commands":              "コマンド:",
+		"help.global_options":        "グローバルオプション:",
+		"help.options":               "オプション:",
+		"help.category":              "カテゴリ:",
+		"help.deprecated":            "非推奨:",
+		"help.copyright":             "著作権:",
+		"help.no_topic":              "'%s' のヘルプはありません",
+		"help.incorrect":             "使い方が正しくありません:",
+		"version.name":               "バージョン",
+		"command.help":               "コマンドの一覧またはコマンドのヘルプを表示します",
+		"command.version":            "バージョンを表示します",
+		"command.config":             "設定を表示します",
+		"command.config_show":        "解決されたオプションの値とその取得元を表示します",
+		"command.alias":              "コマンドのエイリアスを管理します",
+		"command.alias_list":         "エイリアスを一覧表示します",
+		"command.alias_add":          "コマンドラインのエイリアスを追加します",
+		"command.alias_remove":       "エイリアスを削除します",
+		"command.shell":              "対話型シェルでコマンドを実行します",
+		"command.plugin":             "プラグインを実行します",
+		"flag.help":                  "ヘルプを表示します",
+		"flag.help_all":              "非推奨のオプションとコマンドを含むヘルプを表示します",
+		"flag.version":               "バージョンを表示します",
+		"flag.color":                 "出力の色付け: auto, always または never",
+		"flag.output":                "出力形式: json, yaml, xml, table または template=<go template>",
+		"flag.quiet":                 "進捗を表示しません",
+		"flag.no_headers":            "表のヘッダーを表示しません",
+		"flag.print_config":          "解決された設定を表示して終了します",
+		"flag.default":               "既定値",
+		"config.precedence":          "優先順位:",
+		"warning":                    "警告: ",
+		"warning.flag_deprecated":    "オプション '%s' (%s) は非推奨です: %s",
+		"warning.flag_renamed":       "オプション '%s' は非推奨です: 代わりに '%s' を使用してください",
+		"warning.env_renamed":        "環境変数 '%s' は非推奨です: 代わりに '%s' を使用してください",
+		"warning.command_deprecated": "コマンド '%s' は非推奨です: %s",
+		"warning.command_renamed":    "コマンド '%s' は非推奨です: 代わりに '%s' を使用してください",
+		"warning.reload":             "オプション '%s' を再読み込みできません: %v",
+		"warning.signal":             "シグナル '%v' を処理できません: %v",
+		"warning.services":           "サービスを閉じられません: %v",
+		"error.flag":                 "%s: オプション '%v' を設定できません: %w",
+		"error.flag_missing":         "オプション '%s' が見つかりません",
+		"error.command":              "コマンド '%s' が見つかりません",
+		"error.ambiguous":            "コマンド '%s' はあいまいです。候補: %s",
+		"error.template":             "'%s' のテンプレートが正しくありません: %w",
+		"error.signal":               "シグナル '%v' により強制終了しました",
+		"error.alias":                "エイリアス '%s': %w",
+		"error.value":                "サポートされていない値です: %v",
+		"error.output":               "サポートされていない出力形式です: %s",
+		"error.column":               "列 '%s' が見つかりません",
+		"error.brace":                "置換に '}' がありません",
+		"error.interpolation":        "置換が循環しています: %s",
+		"error.quote":                "%[2]q に %[1]c がありません",
+		"error.backslash":            "%q の末尾にバックスラッシュがあります",
+		"error.alias_loop":           "エイリアスが循環しています: %s",
+		"error.alias_map":            "%s: %s はマップでなければなりません",
+		"error.alias_path":           "エイリアスファイルが設定されていません",
+		"error.alias_add":            "エイリアスには名前とコマンドが必要です",
+		"error.alias_remove":         "エイリアスには名前が必要です",
+		"error.alias_name":           "名前が正しくありません",
+		"error.alias_command":        "名前はコマンドで使用されています",
+		"error.alias_missing":        "見つかりません",
+		"error.shell":                "シェルには親コマンドが必要です",
+		"error.service":              "サービス %v: %w",
+		"error.service_missing":      "サービス %v が提供されていません",
+		"error.service_cycle":        "サービスが循環しています: %s",
+	},
+}
+
+// Translate returns the message of the context locale. The id is returned as
+// it is if no catalog contains it.
+func (ctx *Context) Translate(id string, args ...interface{}) string {
+	return translate(ctx.locale(), id, args...)
+}
+
+// locale returns the locale of the closest context that has one or the
+// locale of the environment
+func (ctx *Context) locale() string {
+	for current := ctx; current != nil; current = current.Parent {
+		if current.Locale != "" {
+			return current.Locale
+		}
+	}
+
+	return environLocale(func(key string) (string, bool) {
+		return ctx.getenv(key), true
+	})
+}
+
+// Locale returns the locale of the environment. The LC_ALL, LC_MESSAGES and
+// LANG variables are checked in that order.
+func Locale() string {
+	return environLocale(os.LookupEnv)
+}
+
+func environLocale(lookup func(key string) (string, bool)) string {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value, _ := lookup(name); value != "" {
+			return value
+		}
+	}
+
+	return DefaultLocale
+}
+
+// localize returns the message of the error in the locale
+func localize(locale string, err error) string {
+	switch errx := err.(type) {
+	case *ExitError:
+		return errx.localize(locale)
+	case ExitErrorCollector:
+		messages := make([]string, len(errx))
+
+		for index, err := range errx {
+			messages[index] = localize(locale, err)
+		}
+
+		return strings.Join(messages, "\n")
+	default:
+		return err.Error()
+	}
+}
+
+func translate(locale, id string, args ...interface{}) string {
+	text := id
+
+	for _, name := range candidates(locale) {
+		if message, ok := Catalogs[name][id]; ok {
+			text = message
+			break
+		}
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+
+	values := make([]interface{}, len(args))
+
+	// the wrapped errors are translated as well
+	for index, arg := range args {
+		if err, ok := arg.(error); ok {
+			arg = errors.New(localize(locale, err))
+		}
+
+		values[index] = arg
+	}
+
+	// the messages may wrap errors
+	return fmt.Errorf(text, values...).Error()
+}
+
+// candidates returns the catalog names for a locale such as de_DE.UTF-8 from
+// the most to the least specific
+func candidates(locale string) []string {
+	// the encoding and the modifier are not part of the catalog name
+	if index := strings.IndexAny(locale, ".@"); index >= 0 {
+		locale = locale[:index]
+	}
+
+	locale = strings.ReplaceAll(locale, "-", "_")
+	names := []string{}
+
+	if locale != "" {
+		names = append(names, locale)
+	}
+
+	if language, _, ok := strings.Cut(locale, "_"); ok {
+		names = append(names, language)
+	}
+
+	return append(names, DefaultLocale)
+}
@@ -0,0 +1,183 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Translate", func() {
+	var ctx *cli.Context
+
+	BeforeEach(func() {
+		ctx = &cli.Context{
+			Locale: "de_DE.UTF-8",
+			Command: &cli.Command{
+				Name: "app",
+			},
+		}
+	})
+
+	It("returns the message of the locale", func() {
+		Expect(ctx.Translate("help.commands")).To(Equal("BEFEHLE:"))
+		Expect(ctx.Translate("help.no_topic", "deploy")).To(Equal("Kein Hilfethema für 'deploy'"))
+	})
+
+	It("returns the id if the message does not exist", func() {
+		Expect(ctx.Translate("Deploys the app")).To(Equal("Deploys the app"))
+	})
+
+	Context("when the locale has no catalog", func() {
+		BeforeEach(func() {
+			ctx.Locale = "fr_FR"
+		})
+
+		It("returns the message of the default locale", func() {
+			Expect(ctx.Translate("help.commands")).To(Equal("COMMANDS:"))
+		})
+	})
+
+	Context("when the locale is set by the parent", func() {
+		BeforeEach(func() {
+			ctx = &cli.Context{
+				Parent:  ctx,
+				Command: &cli.Command{},
+			}
+		})
+
+		It("returns the message of the parent locale", func() {
+			Expect(ctx.Translate("help.commands")).To(Equal("BEFEHLE:"))
+		})
+	})
+
+	Context("when the locale is set by the environment", func() {
+		BeforeEach(func() {
+			ctx.Locale = ""
+			Expect(os.Setenv("LC_ALL", "ja_JP.UTF-8")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.Setenv("LC_ALL", "C")).To(Succeed())
+		})
+
+		It("returns the message of the environment locale", func() {
+			Expect(cli.Locale()).To(Equal("ja_JP.UTF-8"))
+			Expect(ctx.Translate("help.commands")).To(Equal("コマンド:"))
+		})
+	})
+
+	Context("when the application has its own messages", func() {
+		BeforeEach(func() {
+			cli.Catalogs["de"]["app.deploy"] = "Verteilt die Anwendung"
+		})
+
+		AfterEach(func() {
+			delete(cli.Catalogs["de"], "app.deploy")
+		})
+
+		It("translates the usage in the help", func() {
+			buffer := NewBuffer()
+
+			ctx.Writer = buffer
+			ctx.Command.Metadata = cli.Map{
+				"Authors": []*cli.Author{},
+			}
+			ctx.Command.Commands = []*cli.Command{
+				cli.NewHelpCommand(),
+				&cli.Command{
+					Name:  "deploy",
+					Usage: "app.deploy",
+				},
+			}
+
+			Expect(cli.NewHelpCommand().Action(ctx)).To(Succeed())
+			Expect(buffer).To(Say("BEFEHLE:"))
+			Expect(buffer).To(Say(`help, h\s+Zeigt eine Liste der Befehle`))
+			Expect(buffer).To(Say(`deploy\s+Verteilt die Anwendung`))
+		})
+	})
+
+	Context("when the application fails", func() {
+		It("prints the error in the locale", func() {
+			buffer := &bytes.Buffer{}
+
+			app := &cli.App{
+				Name:      "app",
+				Locale:    "de",
+				ErrWriter: buffer,
+				Writer:    &bytes.Buffer{},
+				Exit:      func(code int) {},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name",
+						Required: true,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					return nil
+				},
+			}
+
+			app.Run([]string{"app"})
+			Expect(buffer.String()).To(Equal("Option 'name' nicht gefunden\n"))
+		})
+	})
+
+	Context("when a deprecated flag is used", func() {
+		It("prints the warning in the locale", func() {
+			buffer := &bytes.Buffer{}
+
+			app := &cli.App{
+				Name:      "app",
+				Locale:    "de",
+				ErrWriter: buffer,
+				Writer:    &bytes.Buffer{},
+				Exit:      func(code int) {},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:       "name",
+						Deprecated: "use --user",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					return nil
+				},
+			}
+
+			app.Run([]string{"app", "--name", "john"})
+			Expect(buffer.String()).To(Equal("Warnung: Option 'name' (flag:name) ist veraltet: use --user\n"))
+		})
+	})
+
+	Context("when the error wraps another error", func() {
+		It("prints both errors in the locale", func() {
+			buffer := &bytes.Buffer{}
+
+			app := &cli.App{
+				Name:        "app",
+				Locale:      "de",
+				ErrWriter:   buffer,
+				Writer:      &bytes.Buffer{},
+				Exit:        func(code int) {},
+				Interpolate: true,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "dir",
+						Value: "${flag:dir}",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					return nil
+				},
+			}
+
+			app.Run([]string{"app"})
+			Expect(buffer.String()).To(Equal("interpolate: die Option 'dir' konnte nicht gesetzt werden: zyklische Ersetzung: dir -> dir\n"))
+		})
+	})
+})
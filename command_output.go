@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// OutputJSON renders the result as JSON document
+	OutputJSON = "json"
+	// OutputYAML renders the result as YAML document
+	OutputYAML = "yaml"
+	// OutputXML renders the result as XML document
+	OutputXML = "xml"
+	// OutputTable renders the result as column-aligned table
+	OutputTable = "table"
+	// OutputTemplate renders the result with the Go template that follows
+	// the equal sign, e.g. template={{.Name}}
+	OutputTemplate = "template"
+)
+
+// NewOutputFlag creates the --output, -o flag that selects the format used
+// by Context.Render
+func NewOutputFlag() *StringFlag {
+	return &StringFlag{
+		Name:      "output, o",
+		Usage:     "flag.output",
+		Value:     OutputTable,
+		Validator: ValidatorFunc(validateOutput),
+	}
+}
+
+// Render writes the value to the context writer in the format selected by
+// the output flag. The table format is used if the flag is not defined.
+func (ctx *Context) Render(value interface{}) error {
+	return render(ctx, ctx.output(), value)
+}
+
+// output returns the format selected by the output flag
+func (ctx *Context) output() string {
+	if flag := ctx.lookup("output"); flag != nil {
+		if text, ok := flag.Value().(string); ok && text != "" {
+			return text
+		}
+	}
+
+	return OutputTable
+}
+
+func render(ctx *Context, format string, value interface{}) error {
+	var (
+		w          = ctx.Writer
+		name, text = parseOutput(format)
+	)
+
+	switch name {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case OutputYAML:
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(data)
+		return err
+	case OutputXML:
+		data, err := xml.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case OutputTable:
+		return renderTable(ctx, value)
+	case OutputTemplate:
+		return renderTemplate(w, text, value)
+	default:
+		return newError(ExitCodeErrorApp, "error.output", format)
+	}
+}
+
+func renderTemplate(w io.Writer, text string, value interface{}) error {
+	content, err := template.New("output").Parse(text)
+	if err != nil {
+		return err
+	}
+
+	// the template is executed for every item of a list
+	for _, item := range items(value) {
+		if err := content.Execute(w, item); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+func renderTable(ctx *Context, value interface{}) error {
+	header, rows := tabulate(value)
+
+	table := ctx.Table(header...)
+	table.rows = rows
+	return table.Flush()
+}
+
+// tabulate converts a struct, a map or a list of them to a header and rows
+func tabulate(value interface{}) ([]string, [][]string) {
+	var (
+		header []string
+		rows   [][]string
+	)
+
+	list := items(value)
+
+	for _, item := range list {
+		v := reflect.Indirect(reflect.ValueOf(item))
+
+		switch v.Kind() {
+		case reflect.Struct:
+			if header == nil {
+				for index := 0; index < v.NumField(); index++ {
+					if field := v.Type().Field(index); field.IsExported() {
+						header = append(header, field.Name)
+					}
+				}
+			}
+
+			row := []string{}
+
+			for index := 0; index < v.NumField(); index++ {
+				if field := v.Type().Field(index); field.IsExported() {
+					row = append(row, fmt.Sprintf("%v", v.Field(index).Interface()))
+				}
+			}
+
+			rows = append(rows, row)
+		case reflect.Map:
+			if header == nil {
+				for _, key := range v.MapKeys() {
+					header = append(header, fmt.Sprintf("%v", key.Interface()))
+				}
+
+				sort.Strings(header)
+			}
+
+			cells := map[string]string{}
+
+			for _, key := range v.MapKeys() {
+				cells[fmt.Sprintf("%v", key.Interface())] = fmt.Sprintf("%v", v.MapIndex(key).Interface())
+			}
+
+			row := []string{}
+
+			for _, name := range header {
+				row = append(row, cells[name])
+			}
+
+			rows = append(rows, row)
+		case reflect.Invalid:
+			continue
+		default:
+			rows = append(rows, []string{fmt.Sprintf("%v", v.Interface())})
+		}
+	}
+
+	for index, name := range header {
+		header[index] = strings.ToUpper(name)
+	}
+
+	return header, rows
+}
+
+// items returns the elements of a slice or the value itself
+func items(value interface{}) []interface{} {
+	v := reflect.Indirect(reflect.ValueOf(value))
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		list := make([]interface{}, v.Len())
+
+		for index := 0; index < v.Len(); index++ {
+			list[index] = v.Index(index).Interface()
+		}
+
+		return list
+	case reflect.Invalid:
+		return []interface{}{}
+	default:
+		return []interface{}{value}
+	}
+}
+
+func parseOutput(format string) (string, string) {
+	name, text, _ := strings.Cut(format, "=")
+	name = strings.ToLower(strings.TrimSpace(name))
+	return name, text
+}
+
+func validateOutput(ctx *Context, value interface{}) error {
+	format, _ := value.(string)
+
+	switch name, _ := parseOutput(format); name {
+	case OutputJSON, OutputYAML, OutputXML, OutputTable, OutputTemplate:
+		return nil
+	default:
+		return newError(ExitCodeErrorApp, "error.output", format)
+	}
+}
@@ -0,0 +1,164 @@
+package cli_test
+
+import (
+	"bytes"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render", func() {
+	type User struct {
+		Name  string `json:"name" yaml:"name" xml:"name"`
+		Email string `json:"email" yaml:"email" xml:"email"`
+		token string
+	}
+
+	var (
+		ctx    *cli.Context
+		flag   *cli.StringFlag
+		buffer *bytes.Buffer
+		users  []User
+	)
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+
+		flag = cli.NewOutputFlag()
+
+		ctx = &cli.Context{
+			Writer: buffer,
+			Command: &cli.Command{
+				Name:  "app",
+				Flags: []cli.Flag{flag},
+			},
+		}
+
+		users = []User{
+			{Name: "John", Email: "john@example.com", token: "secret"},
+			{Name: "Jack", Email: "jack@example.com", token: "secret"},
+		}
+	})
+
+	It("renders the value as table", func() {
+		Expect(ctx.Render(users)).To(Succeed())
+		Expect(buffer.String()).To(Equal("NAME  EMAIL\nJohn  john@example.com\nJack  jack@example.com\n"))
+	})
+
+	Context("when the value is a map", func() {
+		It("renders the value as table", func() {
+			Expect(ctx.Render(map[string]int{"b": 2, "a": 1})).To(Succeed())
+			Expect(buffer.String()).To(Equal("A  B\n1  2\n"))
+		})
+	})
+
+	Context("when the value is a list of strings", func() {
+		It("renders the value as table without header", func() {
+			Expect(ctx.Render([]string{"alpha", "beta"})).To(Succeed())
+			Expect(buffer.String()).To(Equal("alpha\nbeta\n"))
+		})
+	})
+
+	Context("when the output is json", func() {
+		BeforeEach(func() {
+			flag.Value = "json"
+		})
+
+		It("renders the value as json", func() {
+			Expect(ctx.Render(users[0])).To(Succeed())
+			Expect(buffer.String()).To(Equal("{\n  \"name\": \"John\",\n  \"email\": \"john@example.com\"\n}\n"))
+		})
+	})
+
+	Context("when the output is yaml", func() {
+		BeforeEach(func() {
+			flag.Value = "yaml"
+		})
+
+		It("renders the value as yaml", func() {
+			Expect(ctx.Render(users[0])).To(Succeed())
+			Expect(buffer.String()).To(Equal("name: John\nemail: john@example.com\n"))
+		})
+	})
+
+	Context("when the output is xml", func() {
+		BeforeEach(func() {
+			flag.Value = "xml"
+		})
+
+		It("renders the value as xml", func() {
+			Expect(ctx.Render(users[0])).To(Succeed())
+			Expect(buffer.String()).To(Equal("<User>\n  <name>John</name>\n  <email>john@example.com</email>\n</User>\n"))
+		})
+	})
+
+	Context("when the output is template", func() {
+		BeforeEach(func() {
+			flag.Value = "template={{.Name}}"
+		})
+
+		It("renders every item with the template", func() {
+			Expect(ctx.Render(users)).To(Succeed())
+			Expect(buffer.String()).To(Equal("John\nJack\n"))
+		})
+
+		Context("when the template is not valid", func() {
+			BeforeEach(func() {
+				flag.Value = "template={{.Name"
+			})
+
+			It("returns an error", func() {
+				Expect(ctx.Render(users)).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("when the output is not supported", func() {
+		BeforeEach(func() {
+			flag.Value = "csv"
+		})
+
+		It("returns an error", func() {
+			Expect(ctx.Render(users)).To(MatchError("unsupported output format: csv"))
+		})
+
+		It("fails the validation", func() {
+			Expect(flag.Validate(ctx)).To(MatchError("unsupported output format: csv"))
+		})
+	})
+
+	Context("when the output flag is defined by the parent command", func() {
+		BeforeEach(func() {
+			flag.Value = "template={{.Email}}"
+
+			ctx = &cli.Context{
+				Parent: ctx,
+				Writer: buffer,
+				Command: &cli.Command{
+					Name: "list",
+				},
+			}
+		})
+
+		It("uses the global flag", func() {
+			Expect(ctx.Render(users)).To(Succeed())
+			Expect(buffer.String()).To(Equal("john@example.com\njack@example.com\n"))
+		})
+	})
+
+	Context("when the output flag is set on the command line", func() {
+		It("renders the value in the selected format", func() {
+			cmd := ctx.Command
+			cmd.Action = func(ctx *cli.Context) error {
+				return ctx.Render(users)
+			}
+
+			ctx.Args = []string{"-o", "template={{.Name}}"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(buffer.String()).To(Equal("John\nJack\n"))
+		})
+	})
+})
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// plugins adds the executables named after the command and a subcommand,
+// e.g. app-deploy, as child commands. The plugin directories are searched
+// before the PATH and the existing commands take precedence over the plugins.
+// The directories are read only when the help lists the commands or the
+// prefix of a command is matched.
+func (cmd *Command) plugins(ctx *Context) {
+	if !cmd.Plugins {
+		return
+	}
+
+	var (
+		prefix = cmd.prefix()
+		names  = []string{}
+		paths  = map[string]string{}
+	)
+
+	for _, dir := range cmd.dirs(ctx) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := plugin(entry, prefix)
+
+			if !ok || paths[name] != "" || cmd.find(name) != nil {
+				continue
+			}
+
+			paths[name] = filepath.Join(dir, entry.Name())
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd.plug(name, paths[name])
+	}
+}
+
+// lookup adds the plugin with the name as a child command when no command
+// has the name. It returns nil if there is no such plugin.
+func (cmd *Command) lookup(ctx *Context, name string) *Command {
+	if !cmd.Plugins || name == "" || filepath.Base(name) != name {
+		return nil
+	}
+
+	var (
+		prefix = cmd.prefix()
+		file   = prefix + name
+	)
+
+	if runtime.GOOS == "windows" {
+		file += ".exe"
+	}
+
+	for _, dir := range cmd.dirs(ctx) {
+		path := filepath.Join(dir, file)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if _, ok := plugin(fs.FileInfoToDirEntry(info), prefix); ok {
+			return cmd.plug(name, path)
+		}
+	}
+
+	return nil
+}
+
+func (cmd *Command) plug(name, path string) *Command {
+	command := NewPluginCommand(name, path)
+	command.HelpName = fmt.Sprintf("%s %s", cmd.HelpName, name)
+	command.Middleware = cmd.Middleware
+	cmd.Commands = append(cmd.Commands, command)
+	return command
+}
+
+// prefix returns the prefix of the executables of the plugins
+func (cmd *Command) prefix() string {
+	return strings.ReplaceAll(cmd.HelpName, " ", "-") + "-"
+}
+
+// dirs returns the directories that contain the plugins
+func (cmd *Command) dirs(ctx *Context) []string {
+	dirs := append([]string{}, cmd.PluginDirs...)
+	return append(dirs, filepath.SplitList(ctx.getenv("PATH"))...)
+}
+
+// NewPluginCommand creates a command that runs an external executable. The
+// arguments are passed to the executable and the values of the flags of the
+// parent commands are passed as environment variables.
+func NewPluginCommand(name, path string) *Command {
+	return &Command{
+		Name:            name,
+		Usage:           "command.plugin",
+		ArgsUsage:       "[arguments...]",
+		HideHelp:        true,
+		SkipFlagParsing: true,
+		Action: func(ctx *Context) error {
+			return execute(ctx, path)
+		},
+	}
+}
+
+func execute(ctx *Context, path string) error {
+	command := exec.Command(path, ctx.Args...)
+	command.Stdin = ctx.Reader
+	command.Stdout = ctx.Writer
+	command.Stderr = ctx.ErrWriter
+	command.Env = ctx.environ()
+
+	for name, value := range ctx.EnvVars() {
+		command.Env = append(command.Env, name+"="+value)
+	}
+
+	if err := command.Start(); err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	// the plugin is interrupted together with the application
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = command.Process.Signal(os.Interrupt)
+		case <-stop:
+		}
+	}()
+
+	err := command.Wait()
+
+	var errx *exec.ExitError
+
+	if errors.As(err, &errx) {
+		return WrapError(err).WithCode(errx.ExitCode())
+	}
+
+	return err
+}
+
+// plugin returns the name of the command if the entry is an executable with
+// the prefix
+func plugin(entry os.DirEntry, prefix string) (string, bool) {
+	name := entry.Name()
+
+	if runtime.GOOS == "windows" {
+		if !strings.EqualFold(filepath.Ext(name), ".exe") {
+			return "", false
+		}
+
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+		return "", false
+	}
+
+	info, err := entry.Info()
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o111 == 0 {
+		return "", false
+	}
+
+	return strings.TrimPrefix(name, prefix), true
+}
@@ -0,0 +1,179 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plugin", func() {
+	var (
+		cmd    *cli.Command
+		ctx    *cli.Context
+		dir    string
+		output *bytes.Buffer
+	)
+
+	write := func(name, content string, mode os.FileMode) {
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), mode)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		output = &bytes.Buffer{}
+
+		write("app-deploy", "#!/bin/sh\necho \"deploy $@ $APP_REGION\"\n", 0o700)
+		write("app-fail", "#!/bin/sh\necho failed >&2\nexit 3\n", 0o700)
+		write("app-readme", "not a plugin", 0o600)
+		write("other-tool", "#!/bin/sh\n", 0o700)
+
+		cmd = &cli.Command{
+			Name:       "app",
+			Plugins:    true,
+			PluginDirs: []string{dir},
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "region", EnvVar: "APP_REGION", Value: "eu"},
+			},
+		}
+
+		ctx = &cli.Context{
+			Command:   cmd,
+			Writer:    output,
+			ErrWriter: output,
+			LookupEnv: func(key string) (string, bool) {
+				return "", false
+			},
+		}
+	})
+
+	It("runs the plugin", func() {
+		ctx.Args = []string{"--region", "us", "deploy", "--force", "web"}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(output.String()).To(Equal("deploy --force web us\n"))
+	})
+
+	It("lists the plugins in the help", func() {
+		ctx.Args = []string{"help"}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(output.String()).To(MatchRegexp(`deploy\s+Runs a plugin`))
+		Expect(output.String()).To(MatchRegexp(`fail\s+Runs a plugin`))
+		Expect(output.String()).NotTo(ContainSubstring(dir))
+		Expect(output.String()).NotTo(ContainSubstring("readme"))
+		Expect(output.String()).NotTo(ContainSubstring("other-tool"))
+	})
+
+	It("passes the environment of the context", func() {
+		write("app-env", "#!/bin/sh\necho \"$APP_USER $APP_HOME\"\n", 0o700)
+
+		ctx.Environ = func() []string {
+			return []string{"APP_USER=root", "APP_HOME=/root"}
+		}
+
+		ctx.LookupEnv = func(key string) (string, bool) {
+			if key == "APP_USER" {
+				return "john", true
+			}
+
+			return "", false
+		}
+
+		ctx.Args = []string{"env"}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(output.String()).To(Equal("john \n"))
+	})
+
+	Context("when the plugin fails", func() {
+		It("returns its exit code", func() {
+			ctx.Args = []string{"fail"}
+
+			err := cmd.RunWithContext(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(output.String()).To(Equal("failed\n"))
+
+			errx, ok := err.(cli.ExitCoder)
+			Expect(ok).To(BeTrue())
+			Expect(errx.Code()).To(Equal(3))
+		})
+	})
+
+	Context("when a command has the same name", func() {
+		It("runs the command", func() {
+			cmd.Commands = []*cli.Command{
+				&cli.Command{
+					Name: "deploy",
+					Action: func(ctx *cli.Context) error {
+						return nil
+					},
+				},
+			}
+
+			ctx.Args = []string{"deploy"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(output.String()).To(BeEmpty())
+		})
+
+		It("does not search the plugins", func() {
+			keys := []string{}
+
+			cmd.Commands = []*cli.Command{
+				&cli.Command{
+					Name: "status",
+					Action: func(ctx *cli.Context) error {
+						return nil
+					},
+				},
+			}
+
+			ctx.Args = []string{"status"}
+			ctx.LookupEnv = func(key string) (string, bool) {
+				keys = append(keys, key)
+				return "", false
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(keys).NotTo(ContainElement("PATH"))
+		})
+	})
+
+	It("shows the help of the plugin", func() {
+		ctx.Args = []string{"help", "deploy"}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(output.String()).To(ContainSubstring("app deploy"))
+		Expect(output.String()).To(ContainSubstring("Runs a plugin"))
+	})
+
+	Context("when the prefix matching is allowed", func() {
+		It("runs the plugin", func() {
+			cmd.AllowPrefixMatching = true
+			ctx.Args = []string{"dep", "web"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(output.String()).To(Equal("deploy web eu\n"))
+		})
+	})
+
+	Context("when the plugins are disabled", func() {
+		It("does not run the plugin", func() {
+			cmd.Plugins = false
+			cmd.Action = func(ctx *cli.Context) error {
+				Expect(ctx.Args).To(Equal([]string{"deploy"}))
+				return nil
+			}
+
+			ctx.Args = []string{"deploy"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(output.String()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// the width of the progress bar without the label and the counters
+	progressWidth = 30
+	// the refresh rate of the spinner and of the progress without total
+	progressInterval = 100 * time.Millisecond
+	// clears the current line of the terminal
+	progressClear = "\r\x1b[K"
+)
+
+// NewQuietFlag creates the --quiet, -q flag that turns off the progress bars
+// and the spinners created by Context.Progress and Context.Spinner
+func NewQuietFlag() *BoolFlag {
+	return &BoolFlag{
+		Name:  "quiet, q",
+		Usage: "flag.quiet",
+	}
+}
+
+// Progress shows the progress of a long running operation as a bar. It can be
+// used as io.Writer to track the progress of a copy.
+type Progress struct {
+	// Writer is where the progress bar is written to
+	Writer io.Writer
+	// Label is shown in front of the bar
+	Label string
+	// Total is the expected amount of work. If it is zero only the amount of
+	// completed work is shown.
+	Total int64
+	// Disabled turns off the output
+	Disabled bool
+
+	mu      sync.Mutex
+	current int64
+	percent int
+	drawn   time.Time
+	stopped bool
+	stop    chan struct{}
+}
+
+// NewProgress creates a new progress bar
+func NewProgress(w io.Writer, total int64) *Progress {
+	return &Progress{
+		Writer:  w,
+		Total:   total,
+		percent: -1,
+		stop:    make(chan struct{}),
+	}
+}
+
+// Progress creates a progress bar that writes to the context error writer.
+// The bar is disabled if the writer is not a terminal, the --quiet flag is
+// set or a machine readable output is selected. It stops when the
+// application receives a signal.
+func (ctx *Context) Progress(total int64) *Progress {
+	progress := NewProgress(ctx.ErrWriter, total)
+	progress.Disabled = !ctx.indicate()
+
+	if done := ctx.Done(); done != nil && !progress.Disabled {
+		go func() {
+			select {
+			case <-done:
+				progress.Stop()
+			case <-progress.stop:
+			}
+		}()
+	}
+
+	return progress
+}
+
+// Add adds the amount of completed work
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.set(p.current + n)
+}
+
+// Set sets the amount of completed work
+func (p *Progress) Set(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.set(n)
+}
+
+// Write adds the length of the data to the completed work
+func (p *Progress) Write(data []byte) (int, error) {
+	p.Add(int64(len(data)))
+	return len(data), nil
+}
+
+// Stop completes the progress bar. It is safe to call it more than once.
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return
+	}
+
+	p.stopped = true
+
+	// the channel is nil if the progress is not created by NewProgress
+	if p.stop != nil {
+		close(p.stop)
+	}
+
+	if p.Disabled {
+		return
+	}
+
+	// the final state remains on the screen
+	p.draw()
+	fmt.Fprintln(p.Writer)
+}
+
+func (p *Progress) set(n int64) {
+	if p.stopped {
+		return
+	}
+
+	p.current = n
+
+	if p.Disabled {
+		return
+	}
+
+	if p.Total > 0 {
+		// the bar is redrawn only when it changes
+		if percent := p.percentage(); percent != p.percent {
+			p.percent = percent
+			p.draw()
+		}
+
+		return
+	}
+
+	if now := time.Now(); now.Sub(p.drawn) >= progressInterval {
+		p.drawn = now
+		p.draw()
+	}
+}
+
+func (p *Progress) draw() {
+	line := &strings.Builder{}
+
+	if p.Label != "" {
+		fmt.Fprintf(line, "%s ", p.Label)
+	}
+
+	if p.Total > 0 {
+		percent := p.percentage()
+		filled := progressWidth * percent / 100
+
+		fmt.Fprintf(line, "[%s%s] %3d%% (%d/%d)",
+			strings.Repeat("=", filled),
+			strings.Repeat(" ", progressWidth-filled),
+			percent, p.current, p.Total)
+	} else {
+		fmt.Fprintf(line, "%d", p.current)
+	}
+
+	fmt.Fprint(p.Writer, progressClear+line.String())
+}
+
+func (p *Progress) percentage() int {
+	switch {
+	case p.current <= 0:
+		return 0
+	case p.current >= p.Total:
+		return 100
+	default:
+		return int(p.current * 100 / p.Total)
+	}
+}
+
+// Spinner shows that a long running operation of unknown length is in
+// progress
+type Spinner struct {
+	// Writer is where the spinner is written to
+	Writer io.Writer
+	// Label is shown next to the spinner
+	Label string
+	// Frames of the animation
+	Frames []string
+	// Interval between the frames
+	Interval time.Duration
+	// Disabled turns off the output
+	Disabled bool
+
+	mu      sync.Mutex
+	running bool
+	done    <-chan struct{}
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewSpinner creates a new spinner
+func NewSpinner(w io.Writer, label string) *Spinner {
+	return &Spinner{
+		Writer:   w,
+		Label:    label,
+		Frames:   []string{"|", "/", "-", "\\"},
+		Interval: progressInterval,
+	}
+}
+
+// Spinner creates a spinner that writes to the context error writer. The
+// spinner is disabled if the writer is not a terminal, the --quiet flag is
+// set or a machine readable output is selected. It stops when the
+// application receives a signal.
+func (ctx *Context) Spinner(label string) *Spinner {
+	spinner := NewSpinner(ctx.ErrWriter, label)
+	spinner.Disabled = !ctx.indicate()
+	spinner.done = ctx.Done()
+	return spinner
+}
+
+// Start starts the animation
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running || s.Disabled || len(s.Frames) == 0 {
+		return
+	}
+
+	s.running = true
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+
+	go s.spin(s.stop)
+}
+
+// Stop stops the animation and clears the line. It is safe to call it more
+// than once.
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return
+	}
+
+	s.running = false
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Spinner) spin(stop <-chan struct{}) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		line := s.Frames[frame%len(s.Frames)]
+
+		if s.Label != "" {
+			line = line + " " + s.Label
+		}
+
+		fmt.Fprint(s.Writer, progressClear+line)
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			fmt.Fprint(s.Writer, progressClear)
+			return
+		case <-s.done:
+			fmt.Fprint(s.Writer, progressClear)
+			return
+		}
+	}
+}
+
+// indicate returns true if the progress indicators should be shown
+func (ctx *Context) indicate() bool {
+	if !isTerminal(ctx.ErrWriter) {
+		return false
+	}
+
+	if flag := ctx.lookup("quiet"); flag != nil {
+		if quiet, _ := flag.Value().(bool); quiet {
+			return false
+		}
+	}
+
+	// the progress would get in the way of machine readable output
+	name, _ := parseOutput(ctx.output())
+	return name == OutputTable
+}
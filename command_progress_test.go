@@ -0,0 +1,153 @@
+package cli_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Progress", func() {
+	var (
+		progress *cli.Progress
+		buffer   *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		progress = cli.NewProgress(buffer, 10)
+		progress.Label = "import"
+	})
+
+	It("draws the progress bar", func() {
+		progress.Add(5)
+		Expect(buffer.String()).To(Equal("\r\x1b[Kimport [===============               ]  50% (5/10)"))
+	})
+
+	It("draws the progress bar only when it changes", func() {
+		progress.Set(5)
+		progress.Set(5)
+		Expect(strings.Count(buffer.String(), "import")).To(Equal(1))
+	})
+
+	It("completes the progress bar", func() {
+		progress.Set(10)
+		buffer.Reset()
+
+		progress.Stop()
+		progress.Stop()
+		Expect(buffer.String()).To(Equal("\r\x1b[Kimport [==============================] 100% (10/10)\n"))
+
+		progress.Add(1)
+		Expect(buffer.String()).To(HaveSuffix("\n"))
+	})
+
+	Context("when the progress is created as a literal", func() {
+		It("completes the progress bar", func() {
+			progress = &cli.Progress{Writer: buffer, Total: 3}
+			progress.Set(3)
+			buffer.Reset()
+
+			Expect(progress.Stop).NotTo(Panic())
+			Expect(buffer.String()).To(Equal("\r\x1b[K[==============================] 100% (3/3)\n"))
+		})
+	})
+
+	It("tracks the written data", func() {
+		n, err := io.Copy(progress, strings.NewReader("abcd"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(BeEquivalentTo(4))
+		Expect(buffer.String()).To(HaveSuffix(" 40% (4/10)"))
+	})
+
+	Context("when the total is unknown", func() {
+		BeforeEach(func() {
+			progress.Total = 0
+		})
+
+		It("draws the completed work", func() {
+			progress.Add(42)
+			Expect(buffer.String()).To(Equal("\r\x1b[Kimport 42"))
+		})
+	})
+
+	Context("when the progress is disabled", func() {
+		BeforeEach(func() {
+			progress.Disabled = true
+		})
+
+		It("does not draw", func() {
+			progress.Add(5)
+			progress.Stop()
+			Expect(buffer.Len()).To(BeZero())
+		})
+	})
+
+	Describe("Context", func() {
+		It("disables the progress if the writer is not a terminal", func() {
+			ctx := &cli.Context{
+				ErrWriter: buffer,
+				Command: &cli.Command{
+					Flags: []cli.Flag{cli.NewQuietFlag()},
+				},
+			}
+
+			progress := ctx.Progress(10)
+			Expect(progress.Disabled).To(BeTrue())
+			Expect(progress.Writer).To(Equal(buffer))
+		})
+	})
+})
+
+var _ = Describe("Spinner", func() {
+	var (
+		spinner *cli.Spinner
+		buffer  *Buffer
+	)
+
+	BeforeEach(func() {
+		buffer = NewBuffer()
+		spinner = cli.NewSpinner(buffer, "export")
+		spinner.Interval = time.Millisecond
+	})
+
+	It("spins until it is stopped", func() {
+		spinner.Start()
+		Eventually(buffer).Should(Say(`\| export`))
+		Eventually(buffer).Should(Say(`/ export`))
+
+		spinner.Stop()
+		spinner.Stop()
+		Expect(buffer.Contents()).To(HaveSuffix("\r\x1b[K"))
+	})
+
+	Context("when the spinner is disabled", func() {
+		BeforeEach(func() {
+			spinner.Disabled = true
+		})
+
+		It("does not spin", func() {
+			spinner.Start()
+			spinner.Stop()
+			Expect(buffer.Contents()).To(BeEmpty())
+		})
+	})
+
+	Describe("Context", func() {
+		It("disables the spinner if the writer is not a terminal", func() {
+			ctx := &cli.Context{
+				ErrWriter: buffer,
+				Command:   &cli.Command{},
+			}
+
+			Expect(ctx.Spinner("export").Disabled).To(BeTrue())
+			Expect(ctx.Done()).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+//go:generate counterfeiter -fake-name Terminal -o ./fake/terminal.go . Terminal
+
+// Terminal is the interface used to prompt the user for missing flag values
+type Terminal interface {
+	// Write writes the prompt
+	Write(p []byte) (int, error)
+	// IsTerminal returns true if the input is an interactive terminal
+	IsTerminal() bool
+	// ReadLine reads a line of input
+	ReadLine() (string, error)
+	// ReadPassword reads a line of input without echo
+	ReadPassword() (string, error)
+}
+
+var _ Terminal = &FileTerminal{}
+
+// FileTerminal is a terminal that reads the input from a file such as os.Stdin
+type FileTerminal struct {
+	// File is the input of the terminal
+	File *os.File
+	// Writer writes the prompts
+	Writer io.Writer
+
+	reader *bufio.Reader
+}
+
+// NewTerminal creates a new terminal for the given input and output
+func NewTerminal(file *os.File, writer io.Writer) *FileTerminal {
+	return &FileTerminal{
+		File:   file,
+		Writer: writer,
+		reader: bufio.NewReader(file),
+	}
+}
+
+// Write writes the prompt
+func (t *FileTerminal) Write(p []byte) (int, error) {
+	return t.Writer.Write(p)
+}
+
+// IsTerminal returns true if the input is an interactive terminal
+func (t *FileTerminal) IsTerminal() bool {
+	return term.IsTerminal(int(t.File.Fd()))
+}
+
+// ReadLine reads a line of input
+func (t *FileTerminal) ReadLine() (string, error) {
+	line, err := t.reader.ReadString('\n')
+
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// ReadPassword reads a line of input without echo
+func (t *FileTerminal) ReadPassword() (string, error) {
+	data, err := term.ReadPassword(int(t.File.Fd()))
+	// the new line is not echoed either
+	fmt.Fprintln(t.Writer)
+	return string(data), err
+}
+
+func (cmd *Command) prompt(ctx *Context) error {
+	if !cmd.Interactive {
+		return nil
+	}
+
+	if ctx.Terminal == nil || !ctx.Terminal.IsTerminal() {
+		return nil
+	}
+
+	for _, flag := range cmd.Flags {
+		accessor := NewFlagAccessor(flag)
+
+		if !accessor.Required() || accessor.IsPathFlag() || !isZero(accessor.Value()) {
+			continue
+		}
+
+		value, err := cmd.ask(ctx.Terminal, accessor)
+		if err != nil {
+			return FlagError("prompt", accessor.Name(), err)
+		}
+
+		if err := accessor.Set(value); err != nil {
+			return FlagError("prompt", accessor.Name(), err)
+		}
+
+		if value != "" {
+			ctx.track(flag, SourcePrompt, "")
+		}
+	}
+
+	return nil
+}
+
+func (cmd *Command) ask(terminal Terminal, flag *FlagAccessor) (string, error) {
+	label := flag.Usage()
+
+	if label == "" {
+		label = primary(flag.Name())
+	}
+
+	switch {
+	case flag.IsSecretFlag():
+		fmt.Fprintf(terminal, "%s: ", label)
+		return terminal.ReadPassword()
+	case flag.IsBoolFlag():
+		fmt.Fprintf(terminal, "%s [y/N]: ", label)
+
+		answer, err := terminal.ReadLine()
+		if err != nil {
+			return "", err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return "true", nil
+		default:
+			return "false", nil
+		}
+	}
+
+	validator, ok := flag.Validator().(*OneOfValidator)
+	if !ok {
+		fmt.Fprintf(terminal, "%s: ", label)
+		return terminal.ReadLine()
+	}
+
+	fmt.Fprintf(terminal, "%s:\n", label)
+
+	for index, item := range validator.Items {
+		fmt.Fprintf(terminal, "  %d) %v\n", index+1, item)
+	}
+
+	fmt.Fprintf(terminal, "Choose [1-%d]: ", len(validator.Items))
+
+	answer, err := terminal.ReadLine()
+	if err != nil {
+		return "", err
+	}
+
+	answer = strings.TrimSpace(answer)
+
+	// the choice can be either the number or the value itself
+	if index, err := strconv.Atoi(answer); err == nil && index > 0 && index <= len(validator.Items) {
+		answer = fmt.Sprintf("%v", validator.Items[index-1])
+	}
+
+	return answer, nil
+}
+
+func isZero(value interface{}) bool {
+	v := reflect.ValueOf(value)
+
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+
+	return v.IsZero()
+}
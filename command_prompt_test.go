@@ -0,0 +1,221 @@
+package cli_test
+
+import (
+	"fmt"
+
+	"github.com/phogolabs/cli"
+	"github.com/phogolabs/cli/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Prompt", func() {
+	var (
+		cmd      *cli.Command
+		ctx      *cli.Context
+		flag     *cli.StringFlag
+		terminal *fake.Terminal
+		buffer   *Buffer
+	)
+
+	BeforeEach(func() {
+		buffer = NewBuffer()
+
+		terminal = &fake.Terminal{}
+		terminal.IsTerminalReturns(true)
+		terminal.WriteStub = buffer.Write
+		terminal.ReadLineReturns("john", nil)
+
+		flag = &cli.StringFlag{
+			Name:     "name",
+			Usage:    "User name",
+			Required: true,
+		}
+
+		cmd = &cli.Command{
+			Name:        "app",
+			Interactive: true,
+			Flags:       []cli.Flag{flag},
+			Action: func(ctx *cli.Context) error {
+				return nil
+			},
+		}
+
+		ctx = &cli.Context{
+			Command:  cmd,
+			Writer:   GinkgoWriter,
+			Terminal: terminal,
+		}
+	})
+
+	It("prompts for the missing flag", func() {
+		cmd.Action = func(ctx *cli.Context) error {
+			Expect(ctx.String("name")).To(Equal("john"))
+			Expect(ctx.Source("name").Kind).To(Equal(cli.SourcePrompt))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(buffer).To(Say("User name: "))
+		Expect(terminal.ReadLineCallCount()).To(Equal(1))
+	})
+
+	Context("when the flag is set", func() {
+		BeforeEach(func() {
+			ctx.Args = []string{"-name", "jack"}
+		})
+
+		It("does not prompt", func() {
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(terminal.ReadLineCallCount()).To(Equal(0))
+			Expect(ctx.String("name")).To(Equal("jack"))
+		})
+	})
+
+	Context("when the help is shown", func() {
+		It("does not prompt", func() {
+			ctx.Args = []string{"--help"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(terminal.ReadLineCallCount()).To(Equal(0))
+			Expect(terminal.WriteCallCount()).To(Equal(0))
+		})
+
+		It("does not prompt for the flags of the parent command", func() {
+			cmd.Commands = []*cli.Command{
+				&cli.Command{
+					Name: "child",
+					Action: func(ctx *cli.Context) error {
+						return nil
+					},
+				},
+			}
+
+			ctx.Args = []string{"help", "child"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(terminal.ReadLineCallCount()).To(Equal(0))
+
+			ctx.Args = []string{"child"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(terminal.ReadLineCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the command is not interactive", func() {
+		BeforeEach(func() {
+			cmd.Interactive = false
+		})
+
+		It("does not prompt", func() {
+			Expect(cmd.RunWithContext(ctx)).To(MatchError("flag 'name' not found"))
+			Expect(terminal.ReadLineCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the input is not a terminal", func() {
+		BeforeEach(func() {
+			terminal.IsTerminalReturns(false)
+		})
+
+		It("does not prompt", func() {
+			Expect(cmd.RunWithContext(ctx)).To(MatchError("flag 'name' not found"))
+			Expect(terminal.ReadLineCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when reading the input fails", func() {
+		BeforeEach(func() {
+			terminal.ReadLineReturns("", fmt.Errorf("oh no"))
+		})
+
+		It("returns an error", func() {
+			Expect(cmd.RunWithContext(ctx)).To(MatchError("prompt: failed to set a flag 'name': oh no"))
+		})
+	})
+
+	Context("when the flag is secret", func() {
+		var secret *cli.SecretFlag
+
+		BeforeEach(func() {
+			secret = &cli.SecretFlag{
+				Name:     "password",
+				Required: true,
+			}
+
+			terminal.ReadPasswordReturns("swordfish", nil)
+			cmd.Flags = []cli.Flag{secret}
+		})
+
+		It("reads the value without echo", func() {
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(buffer).To(Say("password: "))
+			Expect(terminal.ReadPasswordCallCount()).To(Equal(1))
+			Expect(terminal.ReadLineCallCount()).To(Equal(0))
+			Expect(ctx.String("password")).To(Equal("swordfish"))
+		})
+	})
+
+	Context("when the flag is bool", func() {
+		var confirm *cli.BoolFlag
+
+		BeforeEach(func() {
+			confirm = &cli.BoolFlag{
+				Name:     "confirm",
+				Usage:    "Are you sure?",
+				Required: true,
+			}
+
+			terminal.ReadLineReturns("y", nil)
+			cmd.Flags = []cli.Flag{confirm}
+		})
+
+		It("asks for confirmation", func() {
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(buffer).To(Say(`Are you sure\? \[y/N\]: `))
+			Expect(ctx.Bool("confirm")).To(BeTrue())
+		})
+
+		Context("when the answer is no", func() {
+			BeforeEach(func() {
+				terminal.ReadLineReturns("", nil)
+			})
+
+			It("returns an error", func() {
+				Expect(cmd.RunWithContext(ctx)).To(MatchError("flag 'confirm' not found"))
+				Expect(ctx.Bool("confirm")).To(BeFalse())
+			})
+		})
+	})
+
+	Context("when the flag has a choice of values", func() {
+		BeforeEach(func() {
+			flag.Validator = cli.OneOf("debug", "info", "error")
+			terminal.ReadLineReturns("2", nil)
+		})
+
+		It("shows the list of values", func() {
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(buffer).To(Say("User name:"))
+			Expect(buffer).To(Say(`1\) debug`))
+			Expect(buffer).To(Say(`2\) info`))
+			Expect(buffer).To(Say(`3\) error`))
+			Expect(buffer).To(Say(`Choose \[1-3\]: `))
+			Expect(ctx.String("name")).To(Equal("info"))
+		})
+
+		Context("when the answer is a value", func() {
+			BeforeEach(func() {
+				terminal.ReadLineReturns("error", nil)
+			})
+
+			It("sets the value", func() {
+				Expect(cmd.RunWithContext(ctx)).To(Succeed())
+				Expect(ctx.String("name")).To(Equal("error"))
+			})
+		})
+	})
+})
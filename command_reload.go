@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"time"
+)
+
+// reload starts watching the files of the flags while the action runs. The
+// values of the flags are replaced only if all changed files are valid.
+func (cmd *Command) reload(action ActionFunc) ActionFunc {
+	if cmd.OnReload == nil {
+		return action
+	}
+
+	return func(ctx *Context) error {
+		reloader := newReloader(ctx, cmd.OnReload)
+
+		if len(reloader.watches) == 0 {
+			return action(ctx)
+		}
+
+		stop := reloader.start(cmd.ReloadInterval, cmd.ReloadSignals)
+		defer stop()
+
+		return action(ctx)
+	}
+}
+
+// watch is a flag whose value has been read from files
+type watch struct {
+	// ctx of the command that owns the flag
+	ctx      *Context
+	flag     Flag
+	paths    []string
+	contents [][]byte
+}
+
+type reloader struct {
+	ctx      *Context
+	notify   ReloadFunc
+	watches  []*watch
+	rejected [][][]byte
+}
+
+func newReloader(ctx *Context, notify ReloadFunc) *reloader {
+	reloader := &reloader{
+		ctx:    ctx,
+		notify: notify,
+	}
+
+	for current := ctx; current != nil; current = current.Parent {
+		for _, flag := range current.Command.Flags {
+			accessor := NewFlagAccessor(flag)
+
+			// the values of the other sources take precedence over the files
+			if !accessor.IsPathFlag() && current.source(flag).Kind != SourcePath {
+				continue
+			}
+
+			watch := &watch{
+				ctx:  current,
+				flag: flag,
+			}
+
+			for _, path := range split(accessor.Path()) {
+				if path, err := current.expandPath(path); err == nil && path != "" {
+					watch.paths = append(watch.paths, path)
+				}
+			}
+
+			if len(watch.paths) > 0 {
+				watch.contents = watch.read()
+				reloader.watches = append(reloader.watches, watch)
+			}
+		}
+	}
+
+	return reloader
+}
+
+// start polls the files at the interval and checks them when one of the
+// signals is received until the returned function is called
+func (r *reloader) start(interval time.Duration, signals []os.Signal) func() {
+	var (
+		tick    <-chan time.Time
+		ch      = make(chan os.Signal, 1)
+		stop    = make(chan struct{})
+		done    = make(chan struct{})
+		release = func() {}
+	)
+
+	if len(signals) > 0 {
+		release = r.ctx.notify(ch, signals)
+	}
+
+	go func() {
+		defer close(done)
+
+		if interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-tick:
+				r.check()
+			case <-ch:
+				r.check()
+			case <-r.ctx.Done():
+				return
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		release()
+		close(stop)
+		<-done
+	}
+}
+
+// check reloads the flags whose files have changed
+func (r *reloader) check() {
+	var (
+		watches  []*watch
+		stages   []Flag
+		names    []string
+		contents = make([][][]byte, len(r.watches))
+	)
+
+	for index, watch := range r.watches {
+		contents[index] = merge(watch.read(), watch.contents)
+	}
+
+	// the rejected files are reported only once
+	if reflect.DeepEqual(contents, r.rejected) {
+		return
+	}
+
+	for index, watch := range r.watches {
+		if equal(contents[index], watch.contents) {
+			continue
+		}
+
+		name := primary(NewFlagAccessor(watch.flag).Name())
+
+		stage, err := watch.stage(contents[index])
+		if err != nil {
+			r.rejected = contents
+			r.ctx.warn("warning.reload", name, err)
+			return
+		}
+
+		watches = append(watches, watch)
+		stages = append(stages, stage)
+		names = append(names, name)
+	}
+
+	if len(watches) == 0 {
+		return
+	}
+
+	// the flags are changed only when all of them are valid
+	r.ctx.values.Lock()
+
+	for index, watch := range watches {
+		commit(watch.flag, stages[index])
+	}
+
+	r.ctx.values.Unlock()
+
+	for index, watch := range r.watches {
+		watch.contents = contents[index]
+	}
+
+	r.rejected = nil
+	r.notify(r.ctx, names)
+}
+
+// read reads the files of the flag. The content of a missing file is nil.
+func (w *watch) read() [][]byte {
+	provider := &PathProvider{}
+	contents := make([][]byte, len(w.paths))
+
+	for index, path := range w.paths {
+		source, err := provider.open(path)
+		if err != nil {
+			continue
+		}
+
+		if data, err := io.ReadAll(source); err == nil {
+			contents[index] = data
+		}
+
+		source.Close()
+	}
+
+	return contents
+}
+
+// stage parses and validates the contents with a copy of the flag
+func (w *watch) stage(contents [][]byte) (Flag, error) {
+	stage := clone(w.flag)
+	accessor := NewFlagAccessor(stage)
+
+	for _, data := range contents {
+		// the file did not exist when the command started
+		if data == nil {
+			continue
+		}
+
+		if _, err := accessor.ReadFrom(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	// the secrets and the documents are not interpolated at the start either
+	if w.ctx.Command.Interpolate && !accessor.IsSecretFlag() && !accessor.IsPathFlag() {
+		if err := w.ctx.interpolate(accessor, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := accessor.Validate(w.ctx); err != nil {
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+// clone creates a shallow copy of the flag. The documents are decoded into a
+// new value of the same type.
+func clone(flag Flag) Flag {
+	target := reflect.ValueOf(duplicate(flag))
+
+	if value := document(target.Elem()); value.IsValid() {
+		target.Elem().FieldByName("Value").Set(reflect.New(value.Type().Elem()))
+	}
+
+	return target.Interface().(Flag)
+}
+
+// commit replaces the flag with the stage. The documents are swapped, not
+// changed in place, so a document read before the reload stays as it was.
+func commit(flag, stage Flag) {
+	reflect.ValueOf(flag).Elem().Set(reflect.ValueOf(stage).Elem())
+}
+
+// document returns the pointer to the decoded document of a path flag
+func document(flag reflect.Value) reflect.Value {
+	field := flag.FieldByName("Value")
+
+	if !field.IsValid() || field.Kind() != reflect.Interface || field.IsNil() {
+		return reflect.Value{}
+	}
+
+	if value := field.Elem(); value.Kind() == reflect.Ptr && !value.IsNil() {
+		return value
+	}
+
+	return reflect.Value{}
+}
+
+// merge keeps the previous content of the files that have been removed
+func merge(contents, previous [][]byte) [][]byte {
+	for index, data := range contents {
+		if data == nil {
+			contents[index] = previous[index]
+		}
+	}
+
+	return contents
+}
+
+func equal(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for index := range a {
+		if !bytes.Equal(a[index], b[index]) {
+			return false
+		}
+	}
+
+	return true
+}
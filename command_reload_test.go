@@ -0,0 +1,213 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Reload", func() {
+	type Config struct {
+		Replicas int `yaml:"replicas"`
+	}
+
+	var (
+		cmd      *cli.Command
+		ctx      *cli.Context
+		dir      string
+		config   *Config
+		reloaded chan []string
+		errors   *gbytes.Buffer
+	)
+
+	write := func(name, content string) {
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		config = &Config{}
+		reloaded = make(chan []string, 1)
+		errors = gbytes.NewBuffer()
+
+		write("level", "info")
+		write("config.yaml", "replicas: 1")
+
+		cmd = &cli.Command{
+			Name: "app",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name: "level",
+					Path: filepath.Join(dir, "level"),
+				},
+				&cli.YAMLFlag{
+					Name:  "config",
+					Path:  filepath.Join(dir, "config.yaml"),
+					Value: config,
+					Validator: cli.ValidatorFunc(func(ctx *cli.Context, value interface{}) error {
+						if value.(*Config).Replicas < 1 {
+							return fmt.Errorf("replicas must be positive")
+						}
+
+						return nil
+					}),
+				},
+			},
+			ReloadInterval: 10 * time.Millisecond,
+			OnReload: func(ctx *cli.Context, names []string) {
+				reloaded <- names
+			},
+		}
+
+		ctx = &cli.Context{
+			Command:   cmd,
+			Writer:    &bytes.Buffer{},
+			ErrWriter: errors,
+		}
+	})
+
+	It("reloads the changed flags", func() {
+		cmd.Action = func(ctx *cli.Context) error {
+			Expect(ctx.String("level")).To(Equal("info"))
+			Expect(ctx.Get("config").(*Config).Replicas).To(Equal(1))
+
+			write("level", "debug")
+			Eventually(reloaded).Should(Receive(Equal([]string{"level"})))
+			Expect(ctx.String("level")).To(Equal("debug"))
+
+			write("config.yaml", "replicas: 3")
+			Eventually(reloaded).Should(Receive(Equal([]string{"config"})))
+			Expect(ctx.Get("config").(*Config).Replicas).To(Equal(3))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	It("reads the values while the flags are reloaded", func() {
+		cmd.Action = func(ctx *cli.Context) error {
+			write("level", "debug")
+
+			// the race detector reports the reads that are not synchronized
+			Eventually(func() string {
+				return ctx.String("level")
+			}).Should(Equal("debug"))
+
+			Eventually(reloaded).Should(Receive(Equal([]string{"level"})))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	It("reads the documents while the flags are reloaded", func() {
+		cmd.Action = func(ctx *cli.Context) error {
+			previous := ctx.Get("config").(*Config)
+			write("config.yaml", "replicas: 3")
+
+			// the race detector reports the documents changed in place
+			Eventually(func() int {
+				return ctx.Get("config").(*Config).Replicas
+			}).Should(Equal(3))
+
+			Eventually(reloaded).Should(Receive(Equal([]string{"config"})))
+			Expect(previous.Replicas).To(Equal(1))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	Context("when the flag is secret", func() {
+		It("reloads the value as it is", func() {
+			write("password", "p${ss\n")
+
+			cmd.Interpolate = true
+			cmd.Flags = append(cmd.Flags, &cli.SecretFlag{
+				Name: "password",
+				Path: filepath.Join(dir, "password"),
+			})
+
+			cmd.Action = func(ctx *cli.Context) error {
+				Expect(ctx.String("password")).To(Equal("p${ss"))
+
+				write("password", "${HOME}\n")
+				Eventually(reloaded).Should(Receive(Equal([]string{"password"})))
+				Expect(ctx.String("password")).To(Equal("${HOME}"))
+				Expect(errors.Contents()).To(BeEmpty())
+				return nil
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+	})
+
+	Context("when the new file is not valid", func() {
+		It("keeps the old values", func() {
+			cmd.Action = func(ctx *cli.Context) error {
+				write("config.yaml", "replicas: 0")
+				write("level", "debug")
+
+				Eventually(errors).Should(gbytes.Say("warning: reloading the flag 'config' failed: replicas must be positive\n"))
+				Consistently(reloaded, "50ms").ShouldNot(Receive())
+				Expect(ctx.String("level")).To(Equal("info"))
+				Expect(ctx.Get("config").(*Config).Replicas).To(Equal(1))
+
+				write("config.yaml", "replicas: 2")
+				Eventually(reloaded).Should(Receive(ConsistOf("level", "config")))
+				Expect(ctx.String("level")).To(Equal("debug"))
+				Expect(ctx.Get("config").(*Config).Replicas).To(Equal(2))
+				return nil
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+	})
+
+	Context("when the value is provided by the command line", func() {
+		It("does not reload the flag", func() {
+			ctx.Args = []string{"--level", "warn"}
+
+			cmd.Action = func(ctx *cli.Context) error {
+				write("level", "debug")
+
+				Consistently(reloaded, "50ms").ShouldNot(Receive())
+				Expect(ctx.String("level")).To(Equal("warn"))
+				return nil
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+	})
+
+	Context("when the reload signal is received", func() {
+		It("reloads the changed flags", func() {
+			cmd.ReloadInterval = 0
+			cmd.ReloadSignals = []os.Signal{syscall.SIGUSR2}
+
+			cmd.Action = func(ctx *cli.Context) error {
+				write("level", "debug")
+				Consistently(reloaded, "50ms").ShouldNot(Receive())
+
+				process, err := os.FindProcess(os.Getpid())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(process.Signal(syscall.SIGUSR2)).To(Succeed())
+
+				Eventually(reloaded).Should(Receive(Equal([]string{"level"})))
+				Expect(ctx.String("level")).To(Equal("debug"))
+				return nil
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Provide registers a function that creates the service of type T. The
+// function runs on the first Resolve of the type by any command of the tree.
+// The service is closed, if it implements io.Closer, after the After hook of
+// the command of the context has run. The services are closed in the reverse
+// order of their creation. A provider replaces an earlier provider of the same
+// type until the command of the context completes.
+func Provide[T any](ctx *Context, provider func(*Context) (T, error)) {
+	if ctx.services == nil {
+		ctx.services = &registry{}
+	}
+
+	owner := ctx
+
+	// the context passed to a provider is a copy
+	if ctx.origin != nil {
+		owner = ctx.origin
+	}
+
+	ctx.services.register(typeOf[T](), &service{
+		owner: owner,
+		create: func(ctx *Context) (interface{}, error) {
+			return provider(ctx)
+		},
+	})
+}
+
+// Resolve returns the service of type T. It is created on the first call. A
+// provider that resolves itself, directly or through other providers, gets an
+// error, as does any call that resolves the service while it is created.
+func Resolve[T any](ctx *Context) (T, error) {
+	var value T
+
+	if ctx.services == nil {
+		return value, newError(ExitCodeErrorApp, "error.service_missing", typeOf[T]())
+	}
+
+	service, err := ctx.services.resolve(ctx, typeOf[T]())
+	if err != nil {
+		return value, err
+	}
+
+	value, _ = service.(T)
+	return value, nil
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// service is a registered provider and its created value
+type service struct {
+	mu     sync.Mutex
+	owner  *Context
+	create func(*Context) (interface{}, error)
+	value  interface{}
+	// resolving are the services being created while the provider runs
+	resolving []reflect.Type
+	created   bool
+}
+
+// registry contains the services of a command tree
+type registry struct {
+	mu sync.Mutex
+	// services are the providers of every type. The last one is used.
+	services map[reflect.Type][]*service
+	// created are the services in the order of their creation
+	created []*service
+}
+
+func (r *registry) register(kind reflect.Type, item *service) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.services == nil {
+		r.services = make(map[reflect.Type][]*service)
+	}
+
+	services := []*service{}
+
+	// the context replaces its own provider
+	for _, service := range r.services[kind] {
+		if service.owner != item.owner {
+			services = append(services, service)
+		}
+	}
+
+	r.services[kind] = append(services, item)
+}
+
+func (r *registry) resolve(ctx *Context, kind reflect.Type) (interface{}, error) {
+	for _, item := range ctx.resolving {
+		if item == kind {
+			return nil, newError(ExitCodeErrorApp, "error.service_cycle", cycle(ctx.resolving, kind))
+		}
+	}
+
+	r.mu.Lock()
+	services := r.services[kind]
+	r.mu.Unlock()
+
+	if len(services) == 0 {
+		return nil, newError(ExitCodeErrorApp, "error.service_missing", kind)
+	}
+
+	service := services[len(services)-1]
+
+	service.mu.Lock()
+
+	switch {
+	case service.created:
+		defer service.mu.Unlock()
+		return service.value, nil
+	case service.resolving != nil:
+		defer service.mu.Unlock()
+		// the provider resolves itself through a context other than its own
+		return nil, newError(ExitCodeErrorApp, "error.service_cycle", cycle(service.resolving, kind))
+	}
+
+	// the provider gets a copy of the context of the owner that knows the
+	// services being created
+	scope := *service.owner
+	scope.origin = service.owner
+	scope.resolving = append(append([]reflect.Type{}, ctx.resolving...), kind)
+
+	// the lock is not held by the provider, so it can resolve the service
+	// again and get an error instead of a deadlock
+	service.resolving = scope.resolving
+	service.mu.Unlock()
+
+	value, err := service.create(&scope)
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.resolving = nil
+
+	// the failed services are created again on the next call
+	if err != nil {
+		return nil, newError(ExitCodeErrorApp, "error.service", kind, err)
+	}
+
+	service.value = value
+	service.created = true
+
+	r.mu.Lock()
+	r.created = append(r.created, service)
+	r.mu.Unlock()
+
+	return value, nil
+}
+
+// close closes the created services of the context in the reverse order of
+// their creation and removes its providers, so the providers of the parent
+// contexts are used again
+func (r *registry) close(owner *Context) error {
+	if r == nil {
+		return nil
+	}
+
+	var (
+		errs    []error
+		pending []*service
+	)
+
+	r.mu.Lock()
+
+	for index := len(r.created) - 1; index >= 0; index-- {
+		if service := r.created[index]; service.owner == owner {
+			pending = append(pending, service)
+			r.created = append(r.created[:index], r.created[index+1:]...)
+		}
+	}
+
+	for kind, services := range r.services {
+		items := []*service{}
+
+		for _, service := range services {
+			if service.owner != owner {
+				items = append(items, service)
+			}
+		}
+
+		if len(items) == 0 {
+			delete(r.services, kind)
+		} else {
+			r.services[kind] = items
+		}
+	}
+
+	r.mu.Unlock()
+
+	for _, service := range pending {
+		service.mu.Lock()
+
+		if closer, ok := service.value.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		service.value = nil
+		service.created = false
+		service.mu.Unlock()
+	}
+
+	return errors.Join(errs...)
+}
+
+// cycle returns the chain of the services that depend on each other
+func cycle(chain []reflect.Type, kind reflect.Type) string {
+	names := make([]string, 0, len(chain)+1)
+
+	for _, item := range chain {
+		names = append(names, fmt.Sprint(item))
+	}
+
+	names = append(names, fmt.Sprint(kind))
+	return strings.Join(names, " -> ")
+}
@@ -0,0 +1,268 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type Database struct {
+	Name   string
+	events *[]string
+}
+
+func (db *Database) Close() error {
+	*db.events = append(*db.events, "close:"+db.Name)
+	return nil
+}
+
+type Cache struct {
+	DB *Database
+}
+
+func (c *Cache) Close() error {
+	*c.DB.events = append(*c.DB.events, "close:cache")
+	return nil
+}
+
+var _ = Describe("Service", func() {
+	var (
+		cmd    *cli.Command
+		ctx    *cli.Context
+		events []string
+	)
+
+	BeforeEach(func() {
+		events = []string{}
+
+		cmd = &cli.Command{
+			Name: "app",
+			Before: func(ctx *cli.Context) error {
+				cli.Provide(ctx, func(ctx *cli.Context) (*Database, error) {
+					events = append(events, "create:db")
+					return &Database{Name: "db", events: &events}, nil
+				})
+
+				cli.Provide(ctx, func(ctx *cli.Context) (*Cache, error) {
+					events = append(events, "create:cache")
+
+					db, err := cli.Resolve[*Database](ctx)
+					if err != nil {
+						return nil, err
+					}
+
+					return &Cache{DB: db}, nil
+				})
+
+				return nil
+			},
+			After: func(ctx *cli.Context) error {
+				events = append(events, "after")
+				return nil
+			},
+			Commands: []*cli.Command{
+				&cli.Command{
+					Name: "sync",
+					Action: func(ctx *cli.Context) error {
+						cache, err := cli.Resolve[*Cache](ctx)
+						if err != nil {
+							return err
+						}
+
+						db, err := cli.Resolve[*Database](ctx)
+						if err != nil {
+							return err
+						}
+
+						Expect(cache.DB).To(BeIdenticalTo(db))
+						events = append(events, "sync")
+						return nil
+					},
+				},
+				&cli.Command{
+					Name: "noop",
+					Action: func(ctx *cli.Context) error {
+						return nil
+					},
+				},
+			},
+		}
+
+		ctx = &cli.Context{
+			Command: cmd,
+			Writer:  &bytes.Buffer{},
+		}
+	})
+
+	It("resolves the services lazily and closes them in reverse order", func() {
+		ctx.Args = []string{"sync"}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(events).To(Equal([]string{
+			"create:cache",
+			"create:db",
+			"sync",
+			"after",
+			"close:cache",
+			"close:db",
+		}))
+	})
+
+	It("does not create the services that are not resolved", func() {
+		ctx.Args = []string{"noop"}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(events).To(Equal([]string{"after"}))
+	})
+
+	Context("when the service is not provided", func() {
+		It("returns an error", func() {
+			cmd.Before = nil
+			ctx.Args = []string{"sync"}
+
+			Expect(cmd.RunWithContext(ctx)).To(MatchError("service *cli_test.Cache not provided"))
+		})
+	})
+
+	Context("when the provider fails", func() {
+		It("returns an error", func() {
+			cmd.Before = func(ctx *cli.Context) error {
+				cli.Provide(ctx, func(ctx *cli.Context) (*Cache, error) {
+					return nil, fmt.Errorf("oh no")
+				})
+
+				return nil
+			}
+
+			ctx.Args = []string{"sync"}
+
+			Expect(cmd.RunWithContext(ctx)).To(MatchError("service *cli_test.Cache: oh no"))
+		})
+	})
+
+	Context("when the provider resolves itself", func() {
+		It("returns an error", func() {
+			cmd.Before = func(ctx *cli.Context) error {
+				cli.Provide(ctx, func(ctx *cli.Context) (*Cache, error) {
+					_, err := cli.Resolve[*Cache](ctx)
+					return nil, err
+				})
+
+				return nil
+			}
+
+			ctx.Args = []string{"sync"}
+
+			Expect(cmd.RunWithContext(ctx)).To(MatchError("service *cli_test.Cache: service cycle: *cli_test.Cache -> *cli_test.Cache"))
+		})
+
+		Context("when the provider uses the context of the hook", func() {
+			It("returns an error", func() {
+				cmd.Before = func(ctx *cli.Context) error {
+					outer := ctx
+
+					cli.Provide(ctx, func(ctx *cli.Context) (*Cache, error) {
+						_, err := cli.Resolve[*Cache](outer)
+						return nil, err
+					})
+
+					return nil
+				}
+
+				ctx.Args = []string{"sync"}
+
+				Expect(cmd.RunWithContext(ctx)).To(MatchError("service *cli_test.Cache: service cycle: *cli_test.Cache -> *cli_test.Cache"))
+			})
+		})
+	})
+
+	Context("when the providers depend on each other", func() {
+		It("returns an error", func() {
+			cmd.Before = func(ctx *cli.Context) error {
+				cli.Provide(ctx, func(ctx *cli.Context) (*Cache, error) {
+					_, err := cli.Resolve[*Database](ctx)
+					return nil, err
+				})
+
+				cli.Provide(ctx, func(ctx *cli.Context) (*Database, error) {
+					_, err := cli.Resolve[*Cache](ctx)
+					return nil, err
+				})
+
+				return nil
+			}
+
+			ctx.Args = []string{"sync"}
+
+			Expect(cmd.RunWithContext(ctx)).To(MatchError("service *cli_test.Cache: service *cli_test.Database: service cycle: *cli_test.Cache -> *cli_test.Database -> *cli_test.Cache"))
+		})
+	})
+
+	Context("when a child command provides the same type", func() {
+		It("uses the provider of the parent after the child completes", func() {
+			cmd.Commands[0].Before = func(ctx *cli.Context) error {
+				cli.Provide(ctx, func(ctx *cli.Context) (*Database, error) {
+					events = append(events, "create:child")
+					return &Database{Name: "child", events: &events}, nil
+				})
+
+				return nil
+			}
+
+			cmd.Commands[0].Action = func(ctx *cli.Context) error {
+				db, err := cli.Resolve[*Database](ctx)
+				if err != nil {
+					return err
+				}
+
+				events = append(events, "sync:"+db.Name)
+				return nil
+			}
+
+			cmd.After = func(ctx *cli.Context) error {
+				db, err := cli.Resolve[*Database](ctx)
+				if err != nil {
+					return err
+				}
+
+				events = append(events, "after:"+db.Name)
+				return nil
+			}
+
+			ctx.Args = []string{"sync"}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+			Expect(events).To(Equal([]string{
+				"create:child",
+				"sync:child",
+				"close:child",
+				"create:db",
+				"after:db",
+				"close:db",
+			}))
+		})
+	})
+
+	Context("when the service is an interface", func() {
+		It("resolves the service", func() {
+			buffer := &bytes.Buffer{}
+
+			cmd.Action = func(ctx *cli.Context) error {
+				cli.Provide(ctx, func(ctx *cli.Context) (fmt.Stringer, error) {
+					return buffer, nil
+				})
+
+				value, err := cli.Resolve[fmt.Stringer](ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(value).To(BeIdenticalTo(buffer))
+				return nil
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// NewShellCommand creates the shell command that reads the commands of its
+// parent line by line and runs them in the same process. The flags of the
+// parent commands are parsed and their Before hooks run only once.
+func NewShellCommand() *Command {
+	return &Command{
+		Name:   "shell",
+		Usage:  "command.shell",
+		Action: shell,
+	}
+}
+
+// lineReader reads the lines of the shell
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+func shell(ctx *Context) error {
+	parent := ctx.Parent
+
+	if parent == nil {
+		return newError(ExitCodeErrorApp, "error.shell")
+	}
+
+	reader := ctx.lines(parent.Command)
+
+	for {
+		line, err := reader.ReadLine()
+
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		args, err := words(line)
+		if err != nil {
+			ctx.report(err)
+			continue
+		}
+
+		if len(args) == 0 {
+			continue
+		}
+
+		if (args[0] == "exit" || args[0] == "quit") && parent.Command.find(args[0]) == nil {
+			return nil
+		}
+
+		// a failing command does not end the session
+		if err := dispatch(parent, args); err != nil {
+			ctx.report(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+}
+
+// dispatch runs the command line as a subcommand of the context. The flags of
+// the subcommands are restored afterwards, so every line starts with the
+// default values.
+func dispatch(ctx *Context, args []string) error {
+	cmd := ctx.Command
+
+	args, err := cmd.unalias(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	child, args, err := cmd.next(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	if child == nil {
+		return NotFoundCommandError(args[0])
+	}
+
+	return child.RunWithContext(ctx.child(child, args))
+}
+
+// report prints the error of a command line
+func (ctx *Context) report(err error) {
+	if ctx.ErrWriter != nil {
+		fmt.Fprintln(ctx.ErrWriter, localize(ctx.locale(), err))
+	}
+}
+
+// lines returns the reader of the shell. A terminal gets the line editing,
+// the history and the completion of the commands of cmd.
+func (ctx *Context) lines(cmd *Command) lineReader {
+	file, ok := ctx.Terminal.(*FileTerminal)
+
+	if !ok || !file.IsTerminal() {
+		if ctx.Terminal != nil {
+			return ctx.Terminal
+		}
+
+		return &plainReader{reader: bufio.NewReader(ctx.Reader)}
+	}
+
+	rw := struct {
+		io.Reader
+		io.Writer
+	}{file.File, file.Writer}
+
+	terminal := term.NewTerminal(rw, cmd.HelpName+"> ")
+
+	terminal.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+
+		prefix, suggestions := complete(cmd, line[:pos])
+
+		switch {
+		case len(suggestions) == 0:
+			return "", 0, false
+		case len(suggestions) > 1 && common(suggestions) == prefix:
+			fmt.Fprintln(terminal, strings.Join(suggestions, "  "))
+			return "", 0, false
+		}
+
+		word := common(suggestions)
+
+		if len(suggestions) == 1 {
+			word = word + " "
+		}
+
+		text := line[:pos-len(prefix)] + word
+		return text + line[pos:], len(text), true
+	}
+
+	return &termReader{
+		fd:       int(file.File.Fd()),
+		terminal: terminal,
+	}
+}
+
+// termReader reads the lines in raw mode. The mode is restored while the
+// commands run.
+type termReader struct {
+	fd       int
+	terminal *term.Terminal
+}
+
+func (r *termReader) ReadLine() (string, error) {
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+
+	defer term.Restore(r.fd, state)
+
+	return r.terminal.ReadLine()
+}
+
+// plainReader reads the lines from a reader that is not a terminal
+type plainReader struct {
+	reader *bufio.Reader
+}
+
+func (r *plainReader) ReadLine() (string, error) {
+	line, err := r.reader.ReadString('\n')
+
+	if errors.Is(err, io.EOF) && line != "" {
+		err = nil
+	}
+
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// complete returns the word before the cursor and the names of the commands
+// or the flags that start with it
+func complete(cmd *Command, line string) (string, []string) {
+	items, err := words(line)
+	if err != nil {
+		return "", nil
+	}
+
+	prefix := ""
+
+	if len(items) > 0 && !strings.HasSuffix(line, " ") {
+		prefix = items[len(items)-1]
+		items = items[:len(items)-1]
+	}
+
+	for _, item := range items {
+		if child := cmd.find(item); child != nil {
+			cmd = child
+		}
+	}
+
+	suggestions := []string{}
+
+	if strings.HasPrefix(prefix, "-") {
+		for _, flag := range cmd.VisibleFlags() {
+			for _, name := range visible(flag.(*FlagAccessor).Name()) {
+				name = "--" + name
+
+				if len(name) == 3 {
+					name = name[1:]
+				}
+
+				if strings.HasPrefix(name, prefix) {
+					suggestions = append(suggestions, name)
+				}
+			}
+		}
+	} else {
+		for _, child := range cmd.VisibleCommands() {
+			if strings.HasPrefix(child.Name, prefix) {
+				suggestions = append(suggestions, child.Name)
+			}
+		}
+	}
+
+	sort.Strings(suggestions)
+	return prefix, suggestions
+}
+
+// common returns the longest common prefix of the items
+func common(items []string) string {
+	prefix := items[0]
+
+	for _, item := range items[1:] {
+		for !strings.HasPrefix(item, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
@@ -0,0 +1,96 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shell", func() {
+	var (
+		cmd    *cli.Command
+		ctx    *cli.Context
+		calls  []string
+		before int
+		errs   *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		calls = nil
+		before = 0
+		errs = &bytes.Buffer{}
+
+		cmd = &cli.Command{
+			Name: "app",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "region", Value: "eu"},
+			},
+			Before: func(ctx *cli.Context) error {
+				before++
+				return nil
+			},
+			Commands: []*cli.Command{
+				&cli.Command{
+					Name: "deploy",
+					Flags: []cli.Flag{
+						&cli.StringFlag{Name: "env", Value: "dev"},
+						&cli.StringSliceFlag{Name: "tag"},
+					},
+					Action: func(ctx *cli.Context) error {
+						call := fmt.Sprintf("%s %s %v %v", ctx.GlobalString("region"), ctx.String("env"), ctx.StringSlice("tag"), ctx.Args)
+						calls = append(calls, call)
+						return nil
+					},
+				},
+				&cli.Command{
+					Name: "fail",
+					Action: func(ctx *cli.Context) error {
+						return fmt.Errorf("oh no")
+					},
+				},
+				cli.NewShellCommand(),
+			},
+		}
+
+		ctx = &cli.Context{
+			Command:   cmd,
+			Writer:    &bytes.Buffer{},
+			ErrWriter: errs,
+		}
+	})
+
+	It("runs the commands of the lines", func() {
+		ctx.Args = []string{"--region", "us", "shell"}
+		ctx.Reader = strings.NewReader("deploy --env prod --tag a 'web app'\n\ndeploy --tag b\n")
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(calls).To(Equal([]string{
+			"us prod [a] [web app]",
+			"us dev [b] []",
+		}))
+		Expect(before).To(Equal(1))
+	})
+
+	It("continues after a failing command", func() {
+		ctx.Args = []string{"shell"}
+		ctx.Reader = strings.NewReader("fail\nunknown\ndeploy 'x\ndeploy\n")
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(calls).To(Equal([]string{"eu dev [] []"}))
+		Expect(errs.String()).To(Equal("oh no\nmissing ' in \"deploy 'x\"\n"))
+		Expect(ctx.Writer.(*bytes.Buffer).String()).To(ContainSubstring("No help topic for 'unknown'"))
+	})
+
+	It("stops on exit", func() {
+		ctx.Args = []string{"shell"}
+		ctx.Reader = strings.NewReader("deploy\nexit\ndeploy\n")
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(calls).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,337 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Alignment of a table column
+type Alignment int
+
+const (
+	// AlignLeft aligns the column to the left
+	AlignLeft Alignment = iota
+	// AlignRight aligns the column to the right
+	AlignRight
+)
+
+const (
+	// the padding between the columns is the same as in the help output
+	tablePadding = 2
+	// the columns are never shrunk below this width
+	tableMinWidth = 4
+)
+
+// NewNoHeadersFlag creates the --no-headers flag that hides the header of
+// the tables created by Context.Table and Context.Render
+func NewNoHeadersFlag() *BoolFlag {
+	return &BoolFlag{
+		Name:  "no-headers",
+		Usage: "flag.no_headers",
+	}
+}
+
+// Table writes column-aligned rows that fit into the terminal width
+type Table struct {
+	// Writer is where the table is written to
+	Writer io.Writer
+	// Header of the table
+	Header []string
+	// Align contains the alignment of every column. Columns are aligned to
+	// the left by default
+	Align []Alignment
+	// Width is the max width of the table. Zero means unlimited
+	Width int
+	// Wrap wraps the long cells instead of truncating them
+	Wrap bool
+	// NoHeaders hides the header
+	NoHeaders bool
+
+	rows   [][]string
+	sortBy int
+}
+
+// NewTable creates a new table
+func NewTable(w io.Writer, header ...string) *Table {
+	return &Table{
+		Writer: w,
+		Header: header,
+		sortBy: -1,
+	}
+}
+
+// Table creates a table that writes to the context writer. The width of the
+// table is limited to the terminal width and the header is hidden if the
+// --no-headers flag is set.
+func (ctx *Context) Table(header ...string) *Table {
+	table := NewTable(ctx.Writer, header...)
+	table.Width = terminalWidth(ctx.Writer, ctx.getenv("COLUMNS"))
+
+	if flag := ctx.lookup("no-headers"); flag != nil {
+		table.NoHeaders, _ = flag.Value().(bool)
+	}
+
+	return table
+}
+
+// AddRow adds a row to the table
+func (t *Table) AddRow(cells ...interface{}) {
+	row := make([]string, len(cells))
+
+	for index, cell := range cells {
+		row[index] = fmt.Sprintf("%v", cell)
+	}
+
+	t.rows = append(t.rows, row)
+}
+
+// SortBy sorts the rows by the column with the given header name
+func (t *Table) SortBy(name string) error {
+	for index, column := range t.Header {
+		if strings.EqualFold(column, name) {
+			t.sortBy = index
+			return nil
+		}
+	}
+
+	return newError(ExitCodeErrorApp, "error.column", name)
+}
+
+// Flush writes the table
+func (t *Table) Flush() error {
+	rows := t.sort()
+
+	if len(t.Header) > 0 && !t.NoHeaders {
+		rows = append([][]string{t.Header}, rows...)
+	}
+
+	widths := t.fit(rows)
+	writer := tabwriter.NewWriter(t.Writer, 1, 8, tablePadding, ' ', 0)
+
+	for _, row := range rows {
+		for _, line := range t.lines(row, widths) {
+			fmt.Fprintln(writer, strings.Join(line, "\t"))
+		}
+	}
+
+	return writer.Flush()
+}
+
+func (t *Table) sort() [][]string {
+	rows := make([][]string, len(t.rows))
+	copy(rows, t.rows)
+
+	if t.sortBy < 0 {
+		return rows
+	}
+
+	cell := func(row []string) string {
+		if t.sortBy < len(row) {
+			return row[t.sortBy]
+		}
+
+		return ""
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		x, errx := strconv.ParseFloat(cell(rows[i]), 64)
+		y, erry := strconv.ParseFloat(cell(rows[j]), 64)
+
+		// numbers are compared by value
+		if errx == nil && erry == nil {
+			return x < y
+		}
+
+		return less(cell(rows[i]), cell(rows[j]))
+	})
+
+	return rows
+}
+
+// fit returns the widths of the columns shrunk to fit into the table width
+func (t *Table) fit(rows [][]string) []int {
+	widths := []int{}
+
+	for _, row := range rows {
+		for index, cell := range row {
+			if index == len(widths) {
+				widths = append(widths, 0)
+			}
+
+			if size := utf8.RuneCountInString(cell); size > widths[index] {
+				widths[index] = size
+			}
+		}
+	}
+
+	if t.Width <= 0 || len(widths) == 0 {
+		return widths
+	}
+
+	available := t.Width - tablePadding*(len(widths)-1)
+
+	for {
+		total, widest := 0, 0
+
+		for index, width := range widths {
+			total += width
+
+			if width > widths[widest] {
+				widest = index
+			}
+		}
+
+		if total <= available || widths[widest] <= tableMinWidth {
+			return widths
+		}
+
+		widths[widest]--
+	}
+}
+
+// lines returns the lines of a row with its cells truncated or wrapped and
+// aligned to the column widths
+func (t *Table) lines(row []string, widths []int) [][]string {
+	cells := make([][]string, len(row))
+	height := 1
+
+	for index, cell := range row {
+		switch {
+		case utf8.RuneCountInString(cell) <= widths[index]:
+			cells[index] = []string{cell}
+		case t.Wrap:
+			cells[index] = wrap(cell, widths[index])
+		default:
+			cells[index] = []string{truncate(cell, widths[index])}
+		}
+
+		if len(cells[index]) > height {
+			height = len(cells[index])
+		}
+	}
+
+	lines := make([][]string, height)
+
+	for number := range lines {
+		line := make([]string, len(row))
+
+		for index := range row {
+			if number < len(cells[index]) {
+				line[index] = cells[index][number]
+			}
+
+			line[index] = t.align(line[index], index, widths[index], index == len(row)-1)
+		}
+
+		lines[number] = line
+	}
+
+	return lines
+}
+
+func (t *Table) align(cell string, index, width int, last bool) string {
+	padding := width - utf8.RuneCountInString(cell)
+
+	if padding <= 0 {
+		return cell
+	}
+
+	if index < len(t.Align) && t.Align[index] == AlignRight {
+		return strings.Repeat(" ", padding) + cell
+	}
+
+	// the last column does not need trailing spaces
+	if last {
+		return cell
+	}
+
+	return cell + strings.Repeat(" ", padding)
+}
+
+// TerminalWidth returns the width of the terminal. The COLUMNS environment
+// variable takes precedence over the size of the writer's terminal. It
+// returns zero if the width cannot be determined.
+func TerminalWidth(w io.Writer) int {
+	return terminalWidth(w, os.Getenv("COLUMNS"))
+}
+
+func terminalWidth(w io.Writer, columns string) int {
+	if width, err := strconv.Atoi(columns); err == nil && width > 0 {
+		return width
+	}
+
+	if file, ok := w.(*os.File); ok {
+		if width, _, err := term.GetSize(int(file.Fd())); err == nil {
+			return width
+		}
+	}
+
+	return 0
+}
+
+// isTerminal returns true if the writer is a terminal
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+
+	if len(runes) <= width {
+		return text
+	}
+
+	if width <= 1 {
+		return string(runes[:width])
+	}
+
+	return string(runes[:width-1]) + "…"
+}
+
+// wrap breaks the text into lines that are not longer than the width. Words
+// longer than the width are split.
+func wrap(text string, width int) []string {
+	if width <= 0 {
+		return []string{text}
+	}
+
+	var (
+		lines []string
+		line  []rune
+	)
+
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+
+		for len(runes) > 0 {
+			switch {
+			case len(line) == 0 && len(runes) <= width:
+				line, runes = runes, nil
+			case len(line) > 0 && len(line)+1+len(runes) <= width:
+				line = append(append(line, ' '), runes...)
+				runes = nil
+			case len(line) > 0:
+				lines = append(lines, string(line))
+				line = nil
+			default:
+				lines = append(lines, string(runes[:width]))
+				runes = runes[width:]
+			}
+		}
+	}
+
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, string(line))
+	}
+
+	return lines
+}
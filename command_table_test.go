@@ -0,0 +1,163 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	var (
+		table  *cli.Table
+		buffer *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+
+		table = cli.NewTable(buffer, "NAME", "SIZE", "DESCRIPTION")
+		table.AddRow("beta", 20, "the second package")
+		table.AddRow("alpha", 3, "the first package")
+		table.AddRow("gamma", 100, "the third package")
+	})
+
+	It("writes the table", func() {
+		Expect(table.Flush()).To(Succeed())
+		Expect(buffer.String()).To(Equal(
+			"NAME   SIZE  DESCRIPTION\n" +
+				"beta   20    the second package\n" +
+				"alpha  3     the first package\n" +
+				"gamma  100   the third package\n"))
+	})
+
+	Context("when the column is aligned to the right", func() {
+		BeforeEach(func() {
+			table.Align = []cli.Alignment{cli.AlignLeft, cli.AlignRight}
+		})
+
+		It("writes the table", func() {
+			Expect(table.Flush()).To(Succeed())
+			Expect(buffer.String()).To(Equal(
+				"NAME   SIZE  DESCRIPTION\n" +
+					"beta     20  the second package\n" +
+					"alpha     3  the first package\n" +
+					"gamma   100  the third package\n"))
+		})
+	})
+
+	Context("when the headers are hidden", func() {
+		BeforeEach(func() {
+			table.NoHeaders = true
+		})
+
+		It("writes the table", func() {
+			Expect(table.Flush()).To(Succeed())
+			Expect(buffer.String()).To(Equal(
+				"beta   20   the second package\n" +
+					"alpha  3    the first package\n" +
+					"gamma  100  the third package\n"))
+		})
+	})
+
+	Context("when the table is sorted", func() {
+		It("sorts the rows by text", func() {
+			Expect(table.SortBy("name")).To(Succeed())
+			Expect(table.Flush()).To(Succeed())
+			Expect(buffer.String()).To(Equal(
+				"NAME   SIZE  DESCRIPTION\n" +
+					"alpha  3     the first package\n" +
+					"beta   20    the second package\n" +
+					"gamma  100   the third package\n"))
+		})
+
+		It("sorts the rows by number", func() {
+			Expect(table.SortBy("SIZE")).To(Succeed())
+			Expect(table.Flush()).To(Succeed())
+			Expect(buffer.String()).To(Equal(
+				"NAME   SIZE  DESCRIPTION\n" +
+					"alpha  3     the first package\n" +
+					"beta   20    the second package\n" +
+					"gamma  100   the third package\n"))
+		})
+
+		Context("when the column does not exist", func() {
+			It("returns an error", func() {
+				Expect(table.SortBy("date")).To(MatchError("column 'date' not found"))
+			})
+		})
+	})
+
+	Context("when the table is wider than the width", func() {
+		BeforeEach(func() {
+			table.Width = 25
+		})
+
+		It("truncates the long cells", func() {
+			Expect(table.Flush()).To(Succeed())
+			Expect(buffer.String()).To(Equal(
+				"NAME   SIZE  DESCRIPTION\n" +
+					"beta   20    the second …\n" +
+					"alpha  3     the first p…\n" +
+					"gamma  100   the third p…\n"))
+		})
+
+		Context("when the wrapping is enabled", func() {
+			BeforeEach(func() {
+				table.Wrap = true
+			})
+
+			It("wraps the long cells", func() {
+				Expect(table.Flush()).To(Succeed())
+				Expect(buffer.String()).To(Equal(
+					"NAME   SIZE  DESCRIPTION\n" +
+						"beta   20    the second\n" +
+						"             package\n" +
+						"alpha  3     the first\n" +
+						"             package\n" +
+						"gamma  100   the third\n" +
+						"             package\n"))
+			})
+		})
+	})
+
+	Describe("Context", func() {
+		var ctx *cli.Context
+
+		BeforeEach(func() {
+			Expect(os.Setenv("COLUMNS", "25")).To(Succeed())
+
+			ctx = &cli.Context{
+				Writer: buffer,
+				Command: &cli.Command{
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "no-headers",
+							Value: true,
+						},
+					},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.Unsetenv("COLUMNS")).To(Succeed())
+		})
+
+		It("creates a table for the context", func() {
+			table := ctx.Table("NAME")
+			Expect(table.Width).To(Equal(25))
+			Expect(table.NoHeaders).To(BeTrue())
+			Expect(table.Writer).To(Equal(buffer))
+		})
+	})
+
+	Describe("TerminalWidth", func() {
+		It("returns zero if the writer is not a terminal", func() {
+			Expect(cli.TerminalWidth(buffer)).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,326 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"sync"
+
+	"github.com/phogolabs/cli"
+)
+
+type Terminal struct {
+	IsTerminalStub        func() bool
+	isTerminalMutex       sync.RWMutex
+	isTerminalArgsForCall []struct {
+	}
+	isTerminalReturns struct {
+		result1 bool
+	}
+	isTerminalReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	ReadLineStub        func() (string, error)
+	readLineMutex       sync.RWMutex
+	readLineArgsForCall []struct {
+	}
+	readLineReturns struct {
+		result1 string
+		result2 error
+	}
+	readLineReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	ReadPasswordStub        func() (string, error)
+	readPasswordMutex       sync.RWMutex
+	readPasswordArgsForCall []struct {
+	}
+	readPasswordReturns struct {
+		result1 string
+		result2 error
+	}
+	readPasswordReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	WriteStub        func([]byte) (int, error)
+	writeMutex       sync.RWMutex
+	writeArgsForCall []struct {
+		arg1 []byte
+	}
+	writeReturns struct {
+		result1 int
+		result2 error
+	}
+	writeReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Terminal) IsTerminal() bool {
+	fake.isTerminalMutex.Lock()
+	ret, specificReturn := fake.isTerminalReturnsOnCall[len(fake.isTerminalArgsForCall)]
+	fake.isTerminalArgsForCall = append(fake.isTerminalArgsForCall, struct {
+	}{})
+	stub := fake.IsTerminalStub
+	fakeReturns := fake.isTerminalReturns
+	fake.recordInvocation("IsTerminal", []interface{}{})
+	fake.isTerminalMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Terminal) IsTerminalCallCount() int {
+	fake.isTerminalMutex.RLock()
+	defer fake.isTerminalMutex.RUnlock()
+	return len(fake.isTerminalArgsForCall)
+}
+
+func (fake *Terminal) IsTerminalCalls(stub func() bool) {
+	fake.isTerminalMutex.Lock()
+	defer fake.isTerminalMutex.Unlock()
+	fake.IsTerminalStub = stub
+}
+
+func (fake *Terminal) IsTerminalReturns(result1 bool) {
+	fake.isTerminalMutex.Lock()
+	defer fake.isTerminalMutex.Unlock()
+	fake.IsTerminalStub = nil
+	fake.isTerminalReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *Terminal) IsTerminalReturnsOnCall(i int, result1 bool) {
+	fake.isTerminalMutex.Lock()
+	defer fake.isTerminalMutex.Unlock()
+	fake.IsTerminalStub = nil
+	if fake.isTerminalReturnsOnCall == nil {
+		fake.isTerminalReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isTerminalReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *Terminal) ReadLine() (string, error) {
+	fake.readLineMutex.Lock()
+	ret, specificReturn := fake.readLineReturnsOnCall[len(fake.readLineArgsForCall)]
+	fake.readLineArgsForCall = append(fake.readLineArgsForCall, struct {
+	}{})
+	stub := fake.ReadLineStub
+	fakeReturns := fake.readLineReturns
+	fake.recordInvocation("ReadLine", []interface{}{})
+	fake.readLineMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Terminal) ReadLineCallCount() int {
+	fake.readLineMutex.RLock()
+	defer fake.readLineMutex.RUnlock()
+	return len(fake.readLineArgsForCall)
+}
+
+func (fake *Terminal) ReadLineCalls(stub func() (string, error)) {
+	fake.readLineMutex.Lock()
+	defer fake.readLineMutex.Unlock()
+	fake.ReadLineStub = stub
+}
+
+func (fake *Terminal) ReadLineReturns(result1 string, result2 error) {
+	fake.readLineMutex.Lock()
+	defer fake.readLineMutex.Unlock()
+	fake.ReadLineStub = nil
+	fake.readLineReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Terminal) ReadLineReturnsOnCall(i int, result1 string, result2 error) {
+	fake.readLineMutex.Lock()
+	defer fake.readLineMutex.Unlock()
+	fake.ReadLineStub = nil
+	if fake.readLineReturnsOnCall == nil {
+		fake.readLineReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.readLineReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Terminal) ReadPassword() (string, error) {
+	fake.readPasswordMutex.Lock()
+	ret, specificReturn := fake.readPasswordReturnsOnCall[len(fake.readPasswordArgsForCall)]
+	fake.readPasswordArgsForCall = append(fake.readPasswordArgsForCall, struct {
+	}{})
+	stub := fake.ReadPasswordStub
+	fakeReturns := fake.readPasswordReturns
+	fake.recordInvocation("ReadPassword", []interface{}{})
+	fake.readPasswordMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Terminal) ReadPasswordCallCount() int {
+	fake.readPasswordMutex.RLock()
+	defer fake.readPasswordMutex.RUnlock()
+	return len(fake.readPasswordArgsForCall)
+}
+
+func (fake *Terminal) ReadPasswordCalls(stub func() (string, error)) {
+	fake.readPasswordMutex.Lock()
+	defer fake.readPasswordMutex.Unlock()
+	fake.ReadPasswordStub = stub
+}
+
+func (fake *Terminal) ReadPasswordReturns(result1 string, result2 error) {
+	fake.readPasswordMutex.Lock()
+	defer fake.readPasswordMutex.Unlock()
+	fake.ReadPasswordStub = nil
+	fake.readPasswordReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Terminal) ReadPasswordReturnsOnCall(i int, result1 string, result2 error) {
+	fake.readPasswordMutex.Lock()
+	defer fake.readPasswordMutex.Unlock()
+	fake.ReadPasswordStub = nil
+	if fake.readPasswordReturnsOnCall == nil {
+		fake.readPasswordReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.readPasswordReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Terminal) Write(arg1 []byte) (int, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.writeMutex.Lock()
+	ret, specificReturn := fake.writeReturnsOnCall[len(fake.writeArgsForCall)]
+	fake.writeArgsForCall = append(fake.writeArgsForCall, struct {
+		arg1 []byte
+	}{arg1Copy})
+	stub := fake.WriteStub
+	fakeReturns := fake.writeReturns
+	fake.recordInvocation("Write", []interface{}{arg1Copy})
+	fake.writeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Terminal) WriteCallCount() int {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return len(fake.writeArgsForCall)
+}
+
+func (fake *Terminal) WriteCalls(stub func([]byte) (int, error)) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = stub
+}
+
+func (fake *Terminal) WriteArgsForCall(i int) []byte {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	argsForCall := fake.writeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Terminal) WriteReturns(result1 int, result2 error) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = nil
+	fake.writeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Terminal) WriteReturnsOnCall(i int, result1 int, result2 error) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = nil
+	if fake.writeReturnsOnCall == nil {
+		fake.writeReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.writeReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Terminal) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.isTerminalMutex.RLock()
+	defer fake.isTerminalMutex.RUnlock()
+	fake.readLineMutex.RLock()
+	defer fake.readLineMutex.RUnlock()
+	fake.readPasswordMutex.RLock()
+	defer fake.readPasswordMutex.RUnlock()
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Terminal) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ cli.Terminal = new(Terminal)
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -41,14 +42,15 @@ var _ Flag = &StringFlag{}
 
 // StringFlag is a flag with type string
 type StringFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     string
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      string
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -87,18 +89,87 @@ func (f *StringFlag) Validate(ctx *Context) error {
 	return nil
 }
 
+var _ Flag = &SecretFlag{}
+
+// SecretFlag is a flag with type string whose value is never revealed
+type SecretFlag struct {
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      string
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
+}
+
+// String returns the value as string
+func (f *SecretFlag) String() string {
+	return FlagFormat(f)
+}
+
+// Set is called once, in command line order, for each flag present.
+// The flag package may call the String method with a zero-valued receiver,
+// such as a nil pointer.
+func (f *SecretFlag) Set(value string) error {
+	f.Value = value
+	return nil
+}
+
+// Get is a function that allows the contents of a Value to be retrieved.
+// It wraps the Value interface, rather than being part of it, because it
+// appeared after Go 1 and its compatibility rules. All Value types provided
+// by this package satisfy the Getter interface.
+func (f *SecretFlag) Get() interface{} {
+	return f.Value
+}
+
+// Validate validates the flag
+func (f *SecretFlag) Validate(ctx *Context) error {
+	if f.Required {
+		if f.Value == "" {
+			return NotFoundFlagError(f.Name)
+		}
+	}
+
+	if f.Validator != nil {
+		return f.Validator.Validate(ctx, f.Value)
+	}
+
+	return nil
+}
+
+// IsSecretFlag returns true if the flag is secret
+func (f *SecretFlag) IsSecretFlag() bool {
+	return true
+}
+
+// ReadFrom reads the secret from r until EOF or error. The trailing new line
+// of the file is removed, the same way as for the _FILE environment variables.
+func (f *SecretFlag) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+
+	f.Value = strings.TrimRight(string(data), "\r\n")
+	return int64(len(data)), nil
+}
+
 var _ Flag = &StringSliceFlag{}
 
 // StringSliceFlag is a flag with type *StringSlice
 type StringSliceFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     []string
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      []string
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -147,13 +218,15 @@ var _ Flag = &BoolFlag{}
 
 // BoolFlag is a flag with type bool
 type BoolFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     bool
-	Hidden    bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      bool
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // IsBoolFlag returns true if the flag is bool
@@ -187,8 +260,15 @@ func (f *BoolFlag) Get() interface{} {
 	return f.Value
 }
 
-// Validate validates the flag
+// Validate validates the flag. A required bool flag must be true, which makes
+// it suitable for confirmations.
 func (f *BoolFlag) Validate(ctx *Context) error {
+	if f.Required {
+		if !f.Value {
+			return NotFoundFlagError(f.Name)
+		}
+	}
+
 	if f.Validator != nil {
 		return f.Validator.Validate(ctx, f.Value)
 	}
@@ -200,14 +280,15 @@ var _ Flag = &URLFlag{}
 
 // URLFlag is a flag with type url.URL
 type URLFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     *url.URL
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      *url.URL
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -255,14 +336,15 @@ var _ Flag = &JSONFlag{}
 
 // JSONFlag is a flag with type json document
 type JSONFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     interface{}
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      interface{}
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -336,14 +418,15 @@ var _ Flag = &YAMLFlag{}
 
 // YAMLFlag is a flag with type yaml document
 type YAMLFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     interface{}
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      interface{}
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -417,14 +500,15 @@ var _ Flag = &XMLFlag{}
 
 // XMLFlag is a flag with type XMLDocument
 type XMLFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     interface{}
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      interface{}
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -498,15 +582,16 @@ var _ Flag = &TimeFlag{}
 
 // TimeFlag is a flag with type time.Time
 type TimeFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Format    string
-	Value     time.Time
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Format     string
+	Value      time.Time
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -553,14 +638,15 @@ var _ Flag = &DurationFlag{}
 
 // DurationFlag is a flag with type time.Duration
 type DurationFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     time.Duration
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      time.Duration
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -603,14 +689,15 @@ var _ Flag = &IntFlag{}
 
 // IntFlag is a flag with type int
 type IntFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     int
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      int
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -658,14 +745,15 @@ var _ Flag = &Int64Flag{}
 
 // Int64Flag is a flag with type int64
 type Int64Flag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     int64
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      int64
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -708,14 +796,15 @@ var _ Flag = &UIntFlag{}
 
 // UIntFlag is a flag with type uint64
 type UIntFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     uint
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      uint
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -763,14 +852,15 @@ var _ Flag = &UInt64Flag{}
 
 // UInt64Flag is a flag with type uint
 type UInt64Flag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     uint64
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      uint64
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -813,14 +903,15 @@ var _ Flag = &Float32Flag{}
 
 // Float32Flag is a flag with type float32
 type Float32Flag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     float32
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      float32
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -868,14 +959,15 @@ var _ Flag = &Float64Flag{}
 
 // Float64Flag is a flag with type float64
 type Float64Flag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     float64
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      float64
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -918,14 +1010,15 @@ var _ Flag = &IPFlag{}
 
 // IPFlag is a flag with type net.IP
 type IPFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     net.IP
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      net.IP
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -976,14 +1069,15 @@ var _ Flag = &HardwareAddrFlag{}
 
 // HardwareAddrFlag is a flag with type net.HardwareAddr
 type HardwareAddrFlag struct {
-	Name      string
-	Path      string
-	Usage     string
-	EnvVar    string
-	Value     net.HardwareAddr
-	Hidden    bool
-	Required  bool
-	Validator Validator
+	Name       string
+	Path       string
+	Usage      string
+	EnvVar     string
+	Value      net.HardwareAddr
+	Hidden     bool
+	Deprecated string
+	Required   bool
+	Validator  Validator
 }
 
 // String returns the value as string
@@ -1133,6 +1227,45 @@ func (f *FlagAccessor) Hidden() bool {
 	return value.FieldByName("Hidden").Bool()
 }
 
+// Deprecated of the flag
+func (f *FlagAccessor) Deprecated() string {
+	value := reflect.ValueOf(f.Flag)
+	value = reflect.Indirect(value)
+
+	// custom flags might not support deprecation
+	if field := value.FieldByName("Deprecated"); field.IsValid() {
+		return field.String()
+	}
+
+	return ""
+}
+
+// Required of the flag
+func (f *FlagAccessor) Required() bool {
+	value := reflect.ValueOf(f.Flag)
+	value = reflect.Indirect(value)
+
+	if field := value.FieldByName("Required"); field.IsValid() {
+		return field.Bool()
+	}
+
+	return false
+}
+
+// Validator of the flag
+func (f *FlagAccessor) Validator() Validator {
+	value := reflect.ValueOf(f.Flag)
+	value = reflect.Indirect(value)
+
+	if field := value.FieldByName("Validator"); field.IsValid() && !field.IsNil() {
+		if validator, ok := field.Interface().(Validator); ok {
+			return validator
+		}
+	}
+
+	return nil
+}
+
 // Validate validates the flag
 func (f *FlagAccessor) Validate(ctx *Context) error {
 	// FlagValidator validates a given flag
@@ -1175,6 +1308,20 @@ func (f *FlagAccessor) IsPathFlag() bool {
 	return false
 }
 
+// IsSecretFlag returns true if the flag is secret
+func (f *FlagAccessor) IsSecretFlag() bool {
+	// SecretFlag represents a secret flag
+	type SecretFlag interface {
+		IsSecretFlag() bool
+	}
+
+	if flag, ok := f.Flag.(SecretFlag); ok {
+		return flag.IsSecretFlag()
+	}
+
+	return false
+}
+
 // Reset resets the value
 func (f *FlagAccessor) Reset() error {
 	// FlagResetter resets a given flag
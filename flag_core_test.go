@@ -125,6 +125,87 @@ var _ = Describe("StringFlag", func() {
 	})
 })
 
+var _ = Describe("SecretFlag", func() {
+	var flag *cli.SecretFlag
+
+	BeforeEach(func() {
+		flag = &cli.SecretFlag{
+			Name:   "password",
+			Usage:  "database password",
+			EnvVar: "APP_PASSWORD",
+			Path:   "app.config",
+			Value:  "swordfish",
+		}
+	})
+
+	Describe("IsSecretFlag", func() {
+		It("returns true", func() {
+			Expect(flag.IsSecretFlag()).To(BeTrue())
+		})
+	})
+
+	Describe("String", func() {
+		It("returns the flag as string", func() {
+			Expect(flag.String()).To(Equal(cli.FlagFormat(flag)))
+			Expect(flag.String()).NotTo(ContainSubstring("swordfish"))
+		})
+	})
+
+	Describe("Set", func() {
+		It("sets the value successfully", func() {
+			Expect(flag.Set("secret")).To(Succeed())
+			Expect(flag.Value).To(Equal("secret"))
+		})
+	})
+
+	Describe("Get", func() {
+		It("gets the value successfully", func() {
+			Expect(flag.Get()).To(Equal("swordfish"))
+		})
+	})
+
+	Describe("ReadFrom", func() {
+		It("removes the trailing new line", func() {
+			n, err := flag.ReadFrom(bytes.NewBufferString("secret\r\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(int64(8)))
+			Expect(flag.Value).To(Equal("secret"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("validates the flag successfully", func() {
+			Expect(flag.Validate(&cli.Context{})).To(Succeed())
+		})
+
+		Context("when the validation fails", func() {
+			It("returns an error", func() {
+				flag.Validator = cli.ValidatorFunc(func(_ *cli.Context, _ interface{}) error {
+					return fmt.Errorf("oh no!")
+				})
+
+				Expect(flag.Validate(&cli.Context{})).To(MatchError("oh no!"))
+			})
+		})
+
+		Context("when the flag is required", func() {
+			BeforeEach(func() {
+				flag.Required = true
+			})
+
+			Context("when the flag's value is not set", func() {
+				BeforeEach(func() {
+					flag.Value = ""
+				})
+
+				It("returns an error", func() {
+					Expect(flag.Validate(&cli.Context{})).To(MatchError("flag 'password' not found"))
+				})
+			})
+		})
+	})
+})
+
 var _ = Describe("StringSliceFlag", func() {
 	var flag *cli.StringSliceFlag
 
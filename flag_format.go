@@ -6,10 +6,35 @@ import (
 	"os"
 	"reflect"
 	"strings"
+
+	"github.com/phogolabs/cli/template"
 )
 
+// DeprecatedPrefix marks a name, an alias or an environment variable as
+// deprecated. For example the name "listen, ~listen-addr" declares that the
+// --listen-addr flag is deprecated in favour of --listen.
+const DeprecatedPrefix = "~"
+
 // FlagFormat formats a flag
 func FlagFormat(flag Flag) string {
+	return format(flag, plain, english)
+}
+
+// painter applies a style to a part of the formatted flag
+type painter func(style, text string) string
+
+func plain(style, text string) string {
+	return text
+}
+
+// translator returns the message with the given id
+type translator func(id string) string
+
+func english(id string) string {
+	return translate(DefaultLocale, id)
+}
+
+func format(flag Flag, paint painter, tr translator) string {
 	buffer := &bytes.Buffer{}
 
 	accessor, ok := flag.(*FlagAccessor)
@@ -17,40 +42,40 @@ func FlagFormat(flag Flag) string {
 		accessor = &FlagAccessor{Flag: flag}
 	}
 
-	formatName(buffer, accessor)
-	formatUsage(buffer, accessor)
-	formatValue(buffer, accessor)
-	formatEnv(buffer, accessor)
-	formatPath(buffer, accessor)
+	formatName(buffer, accessor, paint)
+	formatUsage(buffer, accessor, tr)
+	formatValue(buffer, accessor, paint, tr)
+	formatEnv(buffer, accessor, paint)
+	formatPath(buffer, accessor, paint)
+	formatDeprecated(buffer, accessor)
 
 	return buffer.String()
 }
 
-func formatName(buffer *bytes.Buffer, flag *FlagAccessor) {
-	hide := isBool(flag.Value())
+func formatName(buffer *bytes.Buffer, flag *FlagAccessor, paint painter) {
+	var (
+		hide = isBool(flag.Value())
+		text = &strings.Builder{}
+	)
 
-	for index, name := range split(flag.Name()) {
+	for index, name := range visible(flag.Name()) {
 		if index > 0 {
-			buffer.WriteString(", ")
+			text.WriteString(", ")
 		}
 
-		if len(name) == 1 {
-			buffer.WriteString("-")
-		} else {
-			buffer.WriteString("--")
-		}
-
-		buffer.WriteString(name)
+		text.WriteString(dashed(name))
 
 		if !hide {
-			buffer.WriteString(" value")
+			text.WriteString(" value")
 		}
 	}
 
+	// the names are styled at once to keep the columns aligned
+	buffer.WriteString(paint(template.StyleFlag, text.String()))
 }
 
-func formatUsage(buffer *bytes.Buffer, flag *FlagAccessor) {
-	usage := flag.Usage()
+func formatUsage(buffer *bytes.Buffer, flag *FlagAccessor, tr translator) {
+	usage := tr(flag.Usage())
 
 	if usage == "" {
 		return
@@ -63,7 +88,12 @@ func formatUsage(buffer *bytes.Buffer, flag *FlagAccessor) {
 	buffer.WriteString(usage)
 }
 
-func formatValue(buffer *bytes.Buffer, flag *FlagAccessor) {
+func formatValue(buffer *bytes.Buffer, flag *FlagAccessor, paint painter, tr translator) {
+	// the default value of a secret must not be revealed
+	if flag.IsSecretFlag() {
+		return
+	}
+
 	value := toString(flag.Value())
 
 	if value == "" {
@@ -74,10 +104,10 @@ func formatValue(buffer *bytes.Buffer, flag *FlagAccessor) {
 		buffer.WriteString(" ")
 	}
 
-	fmt.Fprintf(buffer, "(default: %v)", value)
+	fmt.Fprintf(buffer, "(%s: %v)", tr("flag.default"), paint(template.StyleValue, value))
 }
 
-func formatEnv(buffer *bytes.Buffer, flag *FlagAccessor) {
+func formatEnv(buffer *bytes.Buffer, flag *FlagAccessor, paint painter) {
 	envs := flag.EnvVar()
 
 	if envs = strings.TrimSpace(envs); envs == "" {
@@ -90,19 +120,18 @@ func formatEnv(buffer *bytes.Buffer, flag *FlagAccessor) {
 
 	buffer.WriteString("[")
 
-	for index, envar := range split(envs) {
+	for index, envar := range visible(envs) {
 		if index > 0 {
 			buffer.WriteString(", ")
 		}
 
-		buffer.WriteString("$")
-		buffer.WriteString(envar)
+		buffer.WriteString(paint(template.StyleEnv, "$"+envar))
 	}
 
 	buffer.WriteString("]")
 }
 
-func formatPath(buffer *bytes.Buffer, flag *FlagAccessor) {
+func formatPath(buffer *bytes.Buffer, flag *FlagAccessor, paint painter) {
 	path := flag.Path()
 
 	if path = strings.TrimSpace(path); path == "" {
@@ -113,7 +142,92 @@ func formatPath(buffer *bytes.Buffer, flag *FlagAccessor) {
 		buffer.WriteString(" ")
 	}
 
-	fmt.Fprintf(buffer, "[%s]", path)
+	fmt.Fprintf(buffer, "[%s]", paint(template.StyleEnv, path))
+}
+
+func formatDeprecated(buffer *bytes.Buffer, flag *FlagAccessor) {
+	message := flag.Deprecated()
+
+	if message = strings.TrimSpace(message); message == "" {
+		return
+	}
+
+	if buffer.Len() > 0 {
+		buffer.WriteString(" ")
+	}
+
+	fmt.Fprintf(buffer, "(deprecated: %s)", message)
+}
+
+func dashed(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+
+	return "--" + name
+}
+
+// names returns all names in a comma separated list including the deprecated
+// ones without their prefix
+func names(text string) []string {
+	items := split(text)
+
+	for index, item := range items {
+		items[index] = strings.TrimPrefix(item, DeprecatedPrefix)
+	}
+
+	return items
+}
+
+// visible returns the names in a comma separated list that are not deprecated
+func visible(text string) []string {
+	items := []string{}
+
+	for _, item := range split(text) {
+		if !strings.HasPrefix(item, DeprecatedPrefix) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// deprecated returns the deprecated names in a comma separated list without
+// their prefix
+func deprecated(text string) []string {
+	items := []string{}
+
+	for _, item := range split(text) {
+		if strings.HasPrefix(item, DeprecatedPrefix) {
+			items = append(items, strings.TrimPrefix(item, DeprecatedPrefix))
+		}
+	}
+
+	return items
+}
+
+// primary returns the first name in a comma separated list that is not
+// deprecated
+func primary(text string) string {
+	if items := visible(text); len(items) > 0 {
+		return items[0]
+	}
+
+	return ""
+}
+
+func contains(items []string, name string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasPrefixFold(text, prefix string) bool {
+	return len(text) >= len(prefix) && strings.EqualFold(text[:len(prefix)], prefix)
 }
 
 func split(text string) []string {
@@ -169,9 +283,26 @@ func isBool(value interface{}) bool {
 	return v.Kind() == reflect.Bool
 }
 
-func getEnv(name string) string {
-	value := os.Getenv(name)
+func getEnv(ctx *Context, name string) string {
+	value := ctx.getenv(name)
 	value = strings.TrimPrefix(value, "'")
 	value = strings.TrimSuffix(value, "'")
 	return value
 }
+
+func getEnvFile(ctx *Context, name string) (string, error) {
+	path := getEnv(ctx, name+"_FILE")
+
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	value := string(data)
+	value = strings.TrimRight(value, "\r\n")
+	return value, nil
+}
@@ -85,4 +85,46 @@ var _ = Describe("Format", func() {
 			Expect(help).To(Equal("--log-level value, -l value\tApplication log level [$LOG_LEVEL, $LOG_LVL] [logger.conf]"))
 		})
 	})
+
+	Context("when the flag is secret", func() {
+		var flag *cli.SecretFlag
+
+		BeforeEach(func() {
+			flag = &cli.SecretFlag{
+				Name:   "password, p",
+				Usage:  "Database password",
+				EnvVar: "DB_PASSWORD",
+				Value:  "swordfish",
+			}
+		})
+
+		It("does not format the value", func() {
+			help := cli.FlagFormat(flag)
+			Expect(help).To(Equal("--password value, -p value\tDatabase password [$DB_PASSWORD]"))
+		})
+	})
+	Context("when the flag has deprecated names", func() {
+		BeforeEach(func() {
+			flag.Name = "log-level, l, ~log-lvl"
+			flag.EnvVar = "LOG_LEVEL, ~LOG_LVL"
+			flag.Path = ""
+		})
+
+		It("does not format the deprecated names", func() {
+			help := cli.FlagFormat(flag)
+			Expect(help).To(Equal("--log-level value, -l value\tApplication log level (default: info) [$LOG_LEVEL]"))
+		})
+	})
+
+	Context("when the flag is deprecated", func() {
+		BeforeEach(func() {
+			flag.Path = ""
+			flag.Deprecated = "use --verbosity instead"
+		})
+
+		It("formats the deprecation message", func() {
+			help := cli.FlagFormat(flag)
+			Expect(help).To(Equal("--log-level value, -l value\tApplication log level (default: info) [$LOG_LEVEL, $LOG_LVL] (deprecated: use --verbosity instead)"))
+		})
+	})
 })
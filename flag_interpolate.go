@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"strings"
+)
+
+const (
+	// the prefix of the references to other flags, e.g. ${flag:data-dir}
+	interpolateFlag = "flag:"
+	// separates the environment variable from its default, e.g. ${HOME:-/root}
+	interpolateDefault = ":-"
+)
+
+// interpolate expands the references to the environment variables and to
+// the other flags in the values of the flags
+func (cmd *Command) interpolate(ctx *Context) error {
+	if !cmd.Interpolate {
+		return nil
+	}
+
+	// the references to the flags are expanded from the values the flags had
+	// before the interpolation, so every value is expanded only once
+	values := make(map[Flag]string, len(cmd.Flags))
+
+	for _, flag := range cmd.Flags {
+		values[flag] = toString(NewFlagAccessor(flag).Value())
+	}
+
+	for _, flag := range cmd.Flags {
+		accessor := NewFlagAccessor(flag)
+
+		// the documents of the path flags have been read already and the
+		// secrets are taken as they are
+		if accessor.IsPathFlag() || accessor.IsSecretFlag() {
+			continue
+		}
+
+		if err := ctx.interpolate(accessor, values); err != nil {
+			return FlagError("interpolate", accessor.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// interpolate expands the references in the value of the flag. The values
+// are the raw values of the flags that have not been expanded yet.
+func (ctx *Context) interpolate(flag *FlagAccessor, values map[Flag]string) error {
+	interpolator := &interpolator{
+		ctx:    ctx,
+		stack:  []string{primary(flag.Name())},
+		values: values,
+	}
+
+	switch value := flag.Get().(type) {
+	case string:
+		text, err := interpolator.expand(value)
+		if err != nil {
+			return err
+		}
+
+		if text != value {
+			return flag.Flag.Set(text)
+		}
+	case []string:
+		items := make([]string, len(value))
+		changed := false
+
+		for index, item := range value {
+			text, err := interpolator.expand(item)
+			if err != nil {
+				return err
+			}
+
+			items[index] = text
+			changed = changed || text != item
+		}
+
+		if !changed {
+			return nil
+		}
+
+		if err := flag.Reset(); err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := flag.Flag.Set(item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// expand expands the references in the text
+func (ctx *Context) expand(text string) (string, error) {
+	interpolator := &interpolator{
+		ctx: ctx,
+	}
+
+	return interpolator.expand(text)
+}
+
+// expandPath expands the references in the path of a flag if the command
+// interpolates the flags
+func (ctx *Context) expandPath(path string) (string, error) {
+	if ctx.Command == nil || !ctx.Command.Interpolate {
+		return path, nil
+	}
+
+	return ctx.expand(path)
+}
+
+// interpolator expands ${ENV}, ${ENV:-default} and ${flag:name} references.
+// A reference is escaped by a second dollar sign, e.g. $${ENV}.
+type interpolator struct {
+	ctx *Context
+	// the flags that are being expanded
+	stack []string
+	// the raw values of the flags that have not been expanded yet
+	values map[Flag]string
+}
+
+func (i *interpolator) expand(text string) (string, error) {
+	buffer := &strings.Builder{}
+
+	for {
+		start := strings.Index(text, "${")
+
+		if start < 0 {
+			buffer.WriteString(text)
+			return buffer.String(), nil
+		}
+
+		if start > 0 && text[start-1] == '$' {
+			buffer.WriteString(text[:start-1] + "${")
+			text = text[start+2:]
+			continue
+		}
+
+		end := closing(text[start+2:])
+
+		if end < 0 {
+			// the text is not part of the error, because it might be secret
+			return "", newError(ExitCodeErrorFlag, "error.brace")
+		}
+
+		value, err := i.resolve(text[start+2 : start+2+end])
+		if err != nil {
+			return "", err
+		}
+
+		buffer.WriteString(text[:start])
+		buffer.WriteString(value)
+
+		text = text[start+2+end+1:]
+	}
+}
+
+func (i *interpolator) resolve(reference string) (string, error) {
+	if name, ok := strings.CutPrefix(reference, interpolateFlag); ok {
+		return i.flag(strings.TrimSpace(name))
+	}
+
+	name, fallback, ok := strings.Cut(reference, interpolateDefault)
+
+	if value := i.ctx.getenv(strings.TrimSpace(name)); value != "" || !ok {
+		return value, nil
+	}
+
+	return i.expand(fallback)
+}
+
+func (i *interpolator) flag(name string) (string, error) {
+	flag := i.ctx.lookup(name)
+
+	if flag == nil {
+		return "", NotFoundFlagError(name)
+	}
+
+	value, ok := i.values[flag.Flag]
+
+	// the secrets are taken as they are and the other values have been
+	// expanded already
+	if !ok || flag.IsSecretFlag() {
+		return toString(flag.Value()), nil
+	}
+
+	name = primary(flag.Name())
+
+	if contains(i.stack, name) {
+		cycle := append(i.stack, name)
+		return "", newError(ExitCodeErrorFlag, "error.interpolation", strings.Join(cycle, " -> "))
+	}
+
+	i.stack = append(i.stack, name)
+	defer func() {
+		i.stack = i.stack[:len(i.stack)-1]
+	}()
+
+	return i.expand(value)
+}
+
+// closing returns the index of the brace that closes a reference or -1
+func closing(text string) int {
+	depth := 1
+
+	for index := 0; index < len(text); index++ {
+		switch {
+		case strings.HasPrefix(text[index:], "${"):
+			depth++
+			index++
+		case text[index] == '}':
+			depth--
+
+			if depth == 0 {
+				return index
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,230 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/phogolabs/cli"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Interpolation", func() {
+	var (
+		cmd *cli.Command
+		ctx *cli.Context
+		env map[string]string
+	)
+
+	BeforeEach(func() {
+		env = map[string]string{
+			"HOME":     "/home/john",
+			"APP_DATA": "${flag:data-dir}/data",
+		}
+
+		cmd = &cli.Command{
+			Name:        "app",
+			Interpolate: true,
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "data-dir", Value: "${HOME}/.app"},
+				&cli.StringFlag{Name: "cache-dir", Value: "${flag:data-dir}/cache"},
+				&cli.StringFlag{Name: "data", EnvVar: "APP_DATA"},
+			},
+			Action: func(ctx *cli.Context) error {
+				return nil
+			},
+		}
+
+		ctx = &cli.Context{
+			Command: cmd,
+			Writer:  &bytes.Buffer{},
+			LookupEnv: func(key string) (string, bool) {
+				value, ok := env[key]
+				return value, ok
+			},
+		}
+	})
+
+	It("expands the environment variables and the flags", func() {
+		cmd.Action = func(ctx *cli.Context) error {
+			Expect(ctx.String("data-dir")).To(Equal("/home/john/.app"))
+			Expect(ctx.String("cache-dir")).To(Equal("/home/john/.app/cache"))
+			Expect(ctx.String("data")).To(Equal("/home/john/.app/data"))
+			Expect(ctx.Source("data").Kind).To(Equal(cli.SourceEnv))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	It("expands the values of the command line", func() {
+		ctx.Args = []string{"--cache-dir", "${flag:data-dir}/tmp"}
+
+		cmd.Action = func(ctx *cli.Context) error {
+			Expect(ctx.String("cache-dir")).To(Equal("/home/john/.app/tmp"))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	It("expands the flags of the parent command", func() {
+		cmd.Commands = []*cli.Command{
+			&cli.Command{
+				Name: "sync",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{Name: "dir", Value: []string{"${flag:cache-dir}/a", "b"}},
+				},
+				Action: func(ctx *cli.Context) error {
+					Expect(ctx.StringSlice("dir")).To(Equal([]string{"/home/john/.app/cache/a", "b"}))
+					return nil
+				},
+			},
+		}
+
+		ctx.Args = []string{"sync"}
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	It("uses the default of a missing environment variable", func() {
+		cmd.Flags[0] = &cli.StringFlag{Name: "data-dir", Value: "${XDG_DATA_HOME:-${HOME}/.local}/app"}
+
+		cmd.Action = func(ctx *cli.Context) error {
+			Expect(ctx.String("data-dir")).To(Equal("/home/john/.local/app"))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	It("keeps the escaped references", func() {
+		cmd.Flags[0] = &cli.StringFlag{Name: "data-dir", Value: "$${HOME}"}
+
+		cmd.Action = func(ctx *cli.Context) error {
+			Expect(ctx.String("data-dir")).To(Equal("${HOME}"))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	It("keeps the escaped references of the referenced flags", func() {
+		cmd.Flags[0] = &cli.StringFlag{Name: "data-dir", Value: "$${HOME}"}
+
+		cmd.Action = func(ctx *cli.Context) error {
+			Expect(ctx.String("data-dir")).To(Equal("${HOME}"))
+			Expect(ctx.String("cache-dir")).To(Equal("${HOME}/cache"))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	Context("when the referenced flag comes later", func() {
+		It("keeps the escaped references", func() {
+			cmd.Flags = []cli.Flag{
+				&cli.StringFlag{Name: "cache-dir", Value: "${flag:data-dir}/cache"},
+				&cli.StringFlag{Name: "data-dir", Value: "$${HOME}"},
+			}
+
+			cmd.Action = func(ctx *cli.Context) error {
+				Expect(ctx.String("cache-dir")).To(Equal("${HOME}/cache"))
+				return nil
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+	})
+
+	It("expands the paths of the flags", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "token"), []byte("secret"), 0o600)).To(Succeed())
+
+		env["SECRETS_DIR"] = dir
+		cmd.Flags = append(cmd.Flags, &cli.StringFlag{Name: "token", Path: "${SECRETS_DIR}/token"})
+
+		cmd.Action = func(ctx *cli.Context) error {
+			Expect(ctx.String("token")).To(Equal("secret"))
+			return nil
+		}
+
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+	})
+
+	Context("when the references form a cycle", func() {
+		It("returns an error", func() {
+			cmd.Flags[0] = &cli.StringFlag{Name: "data-dir", Value: "${flag:cache-dir}"}
+
+			err := cmd.RunWithContext(ctx)
+			Expect(err).To(MatchError("interpolate: failed to set a flag 'data-dir': interpolation cycle: data-dir -> cache-dir -> data-dir"))
+
+			errx, ok := err.(cli.ExitCoder)
+			Expect(ok).To(BeTrue())
+			Expect(errx.Code()).To(Equal(cli.ExitCodeErrorFlag))
+		})
+	})
+
+	Context("when the flag does not exist", func() {
+		It("returns an error", func() {
+			cmd.Flags[0] = &cli.StringFlag{Name: "data-dir", Value: "${flag:unknown}"}
+
+			Expect(cmd.RunWithContext(ctx)).To(MatchError("interpolate: failed to set a flag 'data-dir': flag 'unknown' not found"))
+		})
+	})
+
+	Context("when the reference is not closed", func() {
+		It("returns an error", func() {
+			cmd.Flags[0] = &cli.StringFlag{Name: "data-dir", Value: "${HOME"}
+
+			Expect(cmd.RunWithContext(ctx)).To(MatchError("interpolate: failed to set a flag 'data-dir': missing '}' in a reference"))
+		})
+	})
+
+	Context("when the interpolation is off", func() {
+		It("keeps the values", func() {
+			cmd.Interpolate = false
+			ctx.Args = []string{"--cache-dir", "echo ${HOME"}
+
+			cmd.Action = func(ctx *cli.Context) error {
+				Expect(ctx.String("data-dir")).To(Equal("${HOME}/.app"))
+				Expect(ctx.String("cache-dir")).To(Equal("echo ${HOME"))
+				return nil
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+	})
+
+	Context("when the flag is secret", func() {
+		BeforeEach(func() {
+			cmd.Flags = append(cmd.Flags, &cli.SecretFlag{Name: "password", Value: "pa${ss"})
+		})
+
+		It("does not expand the value", func() {
+			cmd.Action = func(ctx *cli.Context) error {
+				Expect(ctx.String("password")).To(Equal("pa${ss"))
+				return nil
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+
+		It("does not expand the value of a reference", func() {
+			cmd.Flags[1] = &cli.StringFlag{Name: "cache-dir", Value: "${flag:password}"}
+
+			cmd.Action = func(ctx *cli.Context) error {
+				Expect(ctx.String("cache-dir")).To(Equal("pa${ss"))
+				return nil
+			}
+
+			Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		})
+	})
+
+	It("does not change the flags of the command", func() {
+		Expect(cmd.RunWithContext(ctx)).To(Succeed())
+		Expect(cmd.Flags[0].(*cli.StringFlag).Value).To(Equal("${HOME}/.app"))
+	})
+})
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"flag"
+	"fmt"
 	"io"
 	"net/url"
 	"os"
@@ -16,6 +17,42 @@ import (
 // Map of key value pairs
 type Map map[string]interface{}
 
+// SourceKind represents the kind of a flag's value origin
+type SourceKind string
+
+const (
+	// SourceDefault is the kind of values that have not been changed
+	SourceDefault SourceKind = "default"
+	// SourcePath is the kind of values read from a file
+	SourcePath SourceKind = "path"
+	// SourceEnv is the kind of values read from an environment variable
+	SourceEnv SourceKind = "env"
+	// SourceFlag is the kind of values read from the command line arguments
+	SourceFlag SourceKind = "flag"
+	// SourceProvider is the kind of values set by a custom provider
+	SourceProvider SourceKind = "provider"
+	// SourcePrompt is the kind of values entered interactively by the user
+	SourcePrompt SourceKind = "prompt"
+)
+
+// Source represents the origin of a flag's value
+type Source struct {
+	// Kind of the source
+	Kind SourceKind
+	// Name of the source such as the environment variable, the file path,
+	// the command line flag or the provider type
+	Name string
+}
+
+// String returns the source as string
+func (s *Source) String() string {
+	if s.Name == "" {
+		return string(s.Kind)
+	}
+
+	return fmt.Sprintf("%s:%s", s.Kind, s.Name)
+}
+
 //go:generate counterfeiter -fake-name Provider -o ./fake/provider.go . Provider
 
 // Provider is the interface that parses the flags
@@ -23,6 +60,22 @@ type Provider interface {
 	Provide(*Context) error
 }
 
+// DefaultProviders returns the built-in providers in the order they are
+// applied: the files of the flags, the environment variables, the command
+// line arguments and the documents of the path flags
+func DefaultProviders() []Provider {
+	return []Provider{
+		&PathProvider{
+			IsPathFlag: false,
+		},
+		&EnvProvider{},
+		&FlagProvider{},
+		&PathProvider{
+			IsPathFlag: true,
+		},
+	}
+}
+
 var _ Provider = &FlagProvider{}
 
 // FlagProvider parses the CLI flags
@@ -38,7 +91,7 @@ func (p *FlagProvider) Provide(ctx *Context) error {
 	for _, flag := range ctx.Command.Flags {
 		accessor := NewFlagAccessor(flag)
 
-		for _, key := range split(accessor.Name()) {
+		for _, key := range names(accessor.Name()) {
 			p.set.Var(accessor, key, accessor.Usage())
 		}
 	}
@@ -48,6 +101,12 @@ func (p *FlagProvider) Provide(ctx *Context) error {
 		return err
 	}
 
+	p.set.Visit(func(item *flag.Flag) {
+		if accessor, ok := item.Value.(*FlagAccessor); ok {
+			ctx.track(accessor.Flag, SourceFlag, item.Name)
+		}
+	})
+
 	ctx.Args = p.set.Args()
 	return nil
 }
@@ -62,14 +121,35 @@ func (p *EnvProvider) Provide(ctx *Context) error {
 	for _, flag := range ctx.Command.Flags {
 		accessor := NewFlagAccessor(flag)
 
-		for _, env := range split(accessor.EnvVar()) {
-			value := getEnv(env)
+		for _, env := range names(accessor.EnvVar()) {
+			value := getEnv(ctx, env)
+
+			if !accessor.IsSecretFlag() {
+				for _, value := range split(value) {
+					if err := accessor.Set(value); err != nil {
+						return FlagError("env", accessor.Name(), err)
+					}
+				}
+			} else {
+				// secrets can be provided by a file named in the NAME_FILE variable
+				if value == "" && env != "" {
+					content, err := getEnvFile(ctx, env)
+					if err != nil {
+						return FlagError("env", accessor.Name(), err)
+					}
+
+					value = content
+				}
 
-			for _, value := range split(value) {
+				// secrets are never split as they may contain a comma
 				if err := accessor.Set(value); err != nil {
 					return FlagError("env", accessor.Name(), err)
 				}
 			}
+
+			if value != "" {
+				ctx.track(accessor.Flag, SourceEnv, env)
+			}
 		}
 	}
 
@@ -97,22 +177,14 @@ func (p *PathProvider) Provide(ctx *Context) error {
 				continue
 			}
 
-			root, err := p.root(path)
+			// the path may refer to the environment and to the flags set
+			// by the previous providers
+			path, err := ctx.expandPath(path)
 			if err != nil {
-				return err
+				return FlagError("path", accessor.Name(), err)
 			}
 
-			fs, err := autofs.Lookup(root.String())
-			if err != nil {
-				return err
-			}
-
-			name, err := p.name(path)
-			if err != nil {
-				return err
-			}
-
-			source, err := fs.Open(name)
+			source, err := p.open(path)
 			switch {
 			case os.IsNotExist(err):
 				return nil
@@ -126,12 +198,38 @@ func (p *PathProvider) Provide(ctx *Context) error {
 			if _, err := accessor.ReadFrom(source); err != nil {
 				return err
 			}
+
+			// the document of a path flag is always read from a file, but
+			// the path itself might have been provided by another source
+			if !p.IsPathFlag || !ctx.isSet(accessor.Flag) {
+				ctx.track(accessor.Flag, SourcePath, path)
+			}
 		}
 	}
 
 	return nil
 }
 
+// open opens the file of the path
+func (p *PathProvider) open(path string) (io.ReadCloser, error) {
+	root, err := p.root(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := autofs.Lookup(root.String())
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := p.name(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.Open(name)
+}
+
 func (p *PathProvider) root(path string) (*url.URL, error) {
 	uri, err := url.Parse(path)
 	if err != nil {
@@ -40,6 +40,17 @@ var _ = Describe("Provider", func() {
 		}
 	})
 
+	Describe("DefaultProviders", func() {
+		It("returns the built-in providers in order", func() {
+			Expect(cli.DefaultProviders()).To(Equal([]cli.Provider{
+				&cli.PathProvider{IsPathFlag: false},
+				&cli.EnvProvider{},
+				&cli.FlagProvider{},
+				&cli.PathProvider{IsPathFlag: true},
+			}))
+		})
+	})
+
 	Describe("EnvProvider", func() {
 		var parser *cli.EnvProvider
 
@@ -62,6 +73,12 @@ var _ = Describe("Provider", func() {
 			Expect(flag.Value).To(Equal("8080"))
 		})
 
+		It("tracks the source of the value", func() {
+			Expect(parser.Provide(ctx)).To(Succeed())
+			Expect(ctx.IsSet("listen-addr")).To(BeTrue())
+			Expect(ctx.Source("listen-addr")).To(Equal(&cli.Source{Kind: cli.SourceEnv, Name: "APP_LISTEN_ADDR"}))
+		})
+
 		Context("when the flag is slice", func() {
 			It("sets the value from env variable", func() {
 				Expect(parser.Provide(ctx)).To(Succeed())
@@ -118,6 +135,64 @@ var _ = Describe("Provider", func() {
 				Expect(parser.Provide(ctx)).To(MatchError("env: failed to set a flag 'num': strconv.ParseInt: parsing \"yep\": invalid syntax"))
 			})
 		})
+
+		Context("when the flag is secret", func() {
+			var (
+				secret *cli.SecretFlag
+				path   string
+			)
+
+			BeforeEach(func() {
+				secret = &cli.SecretFlag{
+					Name:   "password",
+					EnvVar: "APP_PASSWORD",
+				}
+
+				ctx.Command.Flags = append(ctx.Command.Flags, secret)
+
+				tmpfile, err := ioutil.TempFile("", "secret")
+				Expect(err).To(BeNil())
+
+				fmt.Fprintln(tmpfile, "swordfish")
+
+				path = tmpfile.Name()
+				Expect(tmpfile.Close()).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(os.Unsetenv("APP_PASSWORD")).To(Succeed())
+				Expect(os.Unsetenv("APP_PASSWORD_FILE")).To(Succeed())
+				Expect(os.Remove(path)).To(Succeed())
+			})
+
+			It("does not split the value", func() {
+				Expect(os.Setenv("APP_PASSWORD", "sword,fish")).To(Succeed())
+				Expect(parser.Provide(ctx)).To(Succeed())
+				Expect(secret.Value).To(Equal("sword,fish"))
+			})
+
+			It("sets the value from the file", func() {
+				Expect(os.Setenv("APP_PASSWORD_FILE", path)).To(Succeed())
+				Expect(parser.Provide(ctx)).To(Succeed())
+				Expect(secret.Value).To(Equal("swordfish"))
+			})
+
+			Context("when the env variable is set", func() {
+				It("takes precedence over the file", func() {
+					Expect(os.Setenv("APP_PASSWORD", "secret")).To(Succeed())
+					Expect(os.Setenv("APP_PASSWORD_FILE", path)).To(Succeed())
+					Expect(parser.Provide(ctx)).To(Succeed())
+					Expect(secret.Value).To(Equal("secret"))
+				})
+			})
+
+			Context("when the file does not exist", func() {
+				It("returns an error", func() {
+					Expect(os.Setenv("APP_PASSWORD_FILE", "/tmp/unknown-secret")).To(Succeed())
+					Expect(parser.Provide(ctx)).To(MatchError("env: failed to set a flag 'password': open /tmp/unknown-secret: no such file or directory"))
+				})
+			})
+		})
 	})
 
 	Describe("PathProvider", func() {
@@ -141,6 +216,12 @@ var _ = Describe("Provider", func() {
 			Expect(flag.Value).To(Equal("9292"))
 		})
 
+		It("tracks the source of the value", func() {
+			Expect(parser.Provide(ctx)).To(Succeed())
+			Expect(ctx.IsSet("listen-addr")).To(BeTrue())
+			Expect(ctx.Source("listen-addr")).To(Equal(&cli.Source{Kind: cli.SourcePath, Name: flag.Path}))
+		})
+
 		Context("when the file path is not valid", func() {
 			It("returns an error", func() {
 				flag.Path = "unknown:///default.conf"
@@ -204,6 +285,15 @@ var _ = Describe("Provider", func() {
 			Expect(flag.Value).To(Equal("9292"))
 		})
 
+		It("tracks the source of the value", func() {
+			Expect(parser.Provide(ctx)).To(Succeed())
+			Expect(ctx.IsSet("listen-addr")).To(BeTrue())
+			Expect(ctx.Source("listen-addr")).To(Equal(&cli.Source{Kind: cli.SourceFlag, Name: "listen-addr"}))
+
+			Expect(ctx.IsSet("user")).To(BeFalse())
+			Expect(ctx.Source("user")).To(Equal(&cli.Source{Kind: cli.SourceDefault}))
+		})
+
 		Context("when the flag is slice", func() {
 			var flagS *cli.StringSliceFlag
 
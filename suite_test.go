@@ -8,6 +8,9 @@ import (
 )
 
 func TestCli(t *testing.T) {
+	// the messages are expected in the default locale
+	t.Setenv("LC_ALL", "C")
+
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Cli Suite")
 }
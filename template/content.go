@@ -2,7 +2,6 @@ package template
 
 import (
 	"embed"
-	"io/ioutil"
 	"strings"
 	"text/template"
 )
@@ -10,21 +9,31 @@ import (
 //go:embed *.tpl
 var content embed.FS
 
-// Open opens the template
-func Open(name string) (*template.Template, error) {
-	file, err := content.Open(name)
+// Open opens the embedded template. The style functions header, command,
+// flag, value and env apply ANSI colors only if color is true. The funcs are
+// merged into the default functions.
+func Open(name string, color bool, funcs template.FuncMap) (*template.Template, error) {
+	data, err := content.ReadFile(name)
 	if err != nil {
 		return nil, err
 	}
 
+	return Parse(name, string(data), color, funcs)
+}
+
+// Parse parses a custom template with the same functions as Open
+func Parse(name, text string, color bool, funcs template.FuncMap) (*template.Template, error) {
 	kv := template.FuncMap{
 		"join": strings.Join,
 	}
 
-	data, err := ioutil.ReadAll(file)
-	if err != nil {
-		return nil, err
+	for key, fn := range styles(color) {
+		kv[key] = fn
+	}
+
+	for key, fn := range funcs {
+		kv[key] = fn
 	}
 
-	return template.New(name).Funcs(kv).Parse(string(data))
+	return template.New(name).Funcs(kv).Parse(text)
 }
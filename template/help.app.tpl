@@ -1,19 +1,22 @@
-NAME:
-   {{.Name}}{{if .Usage}} - {{.Usage}}{{end}}
-USAGE:
-   {{if .UsageText}}{{.UsageText}}{{else}}{{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}{{if .Commands}} command [command options]{{end}} {{if .ArgsUsage}}{{.ArgsUsage}}{{else}}[arguments...]{{end}}{{end}}{{if .Metadata.Version}}{{if not .Metadata.HideVersion}}
-VERSION:
+{{header (t "help.name")}}
+   {{.Name}}{{if .Usage}} - {{t .Usage}}{{end}}
+{{header (t "help.usage")}}
+   {{if .UsageText}}{{.UsageText}}{{else}}{{.HelpName}}{{if .VisibleFlags}} [global options]{{end}}{{if .Commands}} command [command options]{{end}} {{if .ArgsUsage}}{{.ArgsUsage}}{{else}}[arguments...]{{end}}{{end}}{{if .Metadata.Version}}{{if not .Metadata.HideVersion}}
+{{header (t "help.version")}}
    {{.Metadata.Version}}{{end}}{{end}}{{if .Description}}
-DESCRIPTION:
-   {{.Description}}{{end}}{{if len .Metadata.Authors}}
-AUTHOR{{with $length := len .Metadata.Authors}}{{if ne 1 $length}}S{{end}}{{end}}:
+{{header (t "help.description")}}
+   {{t .Description}}{{end}}{{if len .Metadata.Authors}}
+{{with $length := len .Metadata.Authors}}{{if ne 1 $length}}{{header (t "help.authors")}}{{else}}{{header (t "help.author")}}{{end}}{{end}}
    {{range $index, $author := .Metadata.Authors}}{{if $index}}
    {{end}}{{$author}}{{end}}{{end}}{{if .VisibleCommands}}
-COMMANDS:{{range .VisibleCategories}}{{if .Name}}
+{{header (t "help.commands")}}{{range .VisibleCategories}}{{if .Name}}
    {{.Name}}:{{end}}{{range .VisibleCommands}}
-     {{join .Names ", "}}{{"\t"}}{{.Usage}}{{end}}{{end}}{{end}}{{if .Metadata.VisibleFlags}}
-GLOBAL OPTIONS:
+     {{command (join .Names ", ")}}{{"\t"}}{{t .Usage}}{{end}}{{end}}{{end}}{{if .Metadata.VisibleFlags}}
+{{header (t "help.global_options")}}
    {{range $index, $option := .Metadata.VisibleFlags}}{{if $index}}
-   {{end}}{{$option}}{{end}}{{end}}{{if .Metadata.Copyright}}
-COPYRIGHT:
+   {{end}}{{$option}}{{end}}{{end}}{{if .ShowDeprecated}}{{with .DeprecatedItems}}
+{{header (t "help.deprecated")}}
+   {{range $index, $item := .}}{{if $index}}
+   {{end}}{{$item}}{{end}}{{end}}{{end}}{{if .Metadata.Copyright}}
+{{header (t "help.copyright")}}
    {{.Metadata.Copyright}}{{end}}
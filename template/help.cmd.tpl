@@ -1,11 +1,14 @@
-NAME:
-   {{.HelpName}} - {{.Usage}}
-USAGE:
+{{header (t "help.name")}}
+   {{.HelpName}} - {{t .Usage}}
+{{header (t "help.usage")}}
    {{if .UsageText}}{{.UsageText}}{{else}}{{.HelpName}}{{if .Metadata.VisibleFlags}} [command options]{{end}} {{if .ArgsUsage}}{{.ArgsUsage}}{{else}}[arguments...]{{end}}{{end}}{{if .Category}}
-CATEGORY:
+{{header (t "help.category")}}
    {{.Category}}{{end}}{{if .Description}}
-DESCRIPTION:
-   {{.Description}}{{end}}{{if .Metadata.VisibleFlags}}
-OPTIONS:
+{{header (t "help.description")}}
+   {{t .Description}}{{end}}{{if .Metadata.VisibleFlags}}
+{{header (t "help.options")}}
    {{range .Metadata.VisibleFlags}}{{.}}
-   {{end}}{{end}}
+   {{end}}{{end}}{{if .ShowDeprecated}}{{with .DeprecatedItems}}
+{{header (t "help.deprecated")}}
+   {{range .}}{{.}}
+   {{end}}{{end}}{{end}}
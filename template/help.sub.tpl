@@ -1,11 +1,14 @@
-NAME:
-   {{.HelpName}} - {{if .Description}}{{.Description}}{{else}}{{.Usage}}{{end}}
-USAGE:
+{{header (t "help.name")}}
+   {{.HelpName}} - {{if .Description}}{{t .Description}}{{else}}{{t .Usage}}{{end}}
+{{header (t "help.usage")}}
    {{if .UsageText}}{{.UsageText}}{{else}}{{.HelpName}} command{{if .Metadata.VisibleFlags}} [command options]{{end}} {{if .ArgsUsage}}{{.ArgsUsage}}{{else}}[arguments...]{{end}}{{end}}
-COMMANDS:{{range .VisibleCategories}}{{if .Name}}
+{{header (t "help.commands")}}{{range .VisibleCategories}}{{if .Name}}
    {{.Name}}:{{end}}{{range .VisibleCommands}}
-     {{join .Names ", "}}{{"\t"}}{{.Usage}}{{end}}
+     {{command (join .Names ", ")}}{{"\t"}}{{t .Usage}}{{end}}
 {{end}}{{if .Metadata.VisibleFlags}}
-OPTIONS:
+{{header (t "help.options")}}
    {{range .Metadata.VisibleFlags}}{{.}}
-   {{end}}{{end}}
+   {{end}}{{end}}{{if .ShowDeprecated}}{{with .DeprecatedItems}}
+{{header (t "help.deprecated")}}
+   {{range .}}{{.}}
+   {{end}}{{end}}{{end}}
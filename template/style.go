@@ -0,0 +1,50 @@
+package template
+
+import (
+	"text/template"
+)
+
+// The ANSI styles of the help elements
+const (
+	// StyleHeader styles the section headers
+	StyleHeader = "1"
+	// StyleCommand styles the command names
+	StyleCommand = "36"
+	// StyleFlag styles the flag names
+	StyleFlag = "32"
+	// StyleValue styles the default values
+	StyleValue = "2"
+	// StyleEnv styles the environment variables and the paths
+	StyleEnv = "33"
+)
+
+// Colorize wraps the text in the ANSI escape sequence of the style
+func Colorize(style, text string) string {
+	if text == "" {
+		return text
+	}
+
+	return "\x1b[" + style + "m" + text + "\x1b[0m"
+}
+
+// styles returns the style functions of the templates. They return the text
+// unchanged if the color is disabled.
+func styles(color bool) template.FuncMap {
+	fn := func(style string) func(string) string {
+		return func(text string) string {
+			if !color {
+				return text
+			}
+
+			return Colorize(style, text)
+		}
+	}
+
+	return template.FuncMap{
+		"header":  fn(StyleHeader),
+		"command": fn(StyleCommand),
+		"flag":    fn(StyleFlag),
+		"value":   fn(StyleValue),
+		"env":     fn(StyleEnv),
+	}
+}
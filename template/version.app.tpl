@@ -1 +1 @@
-{{ .Name }} version {{ .Metadata.Version }}
+{{ command .Name }} {{ t "version.name" }} {{ .Metadata.Version }}